@@ -0,0 +1,81 @@
+// Package api 定义引擎对外的稳定接口.
+// 外部项目只应导入本包; internal下的实现可以自由重构(容器抽象/规则变体等)
+// 而不破坏下游的兼容性.
+package api
+
+import "github.com/iCode-five/hello-word/internal/game"
+
+// Move 一次倒水动作
+type Move = game.Move
+
+// Logger 引擎消息输出接口, 默认静默
+type Logger = game.Logger
+
+// Difficulty 难度等级与基于求解器的难度报告
+type (
+	Difficulty       = game.Difficulty
+	DifficultyReport = game.DifficultyReport
+)
+
+// ResultCode 对局结果代码
+type ResultCode = game.ResultCode
+
+// Event 引擎事件; EventType/EventHandler 见 internal/game 中的定义
+type (
+	Event        = game.Event
+	EventType    = game.EventType
+	EventHandler = game.EventHandler
+)
+
+// Game 一局可交互的倒水排序游戏
+type Game interface {
+	Pour(from, to int) (int, error)
+	PourAmount(from, to, amount int) (int, error)
+	CanPour(from, to int) (bool, int, string)
+	AutoPour(from int) (Move, error)
+	GetPourFailureReason(from, to int) string
+	Undo() bool
+	UndoToLastCheckpoint() bool
+	IsWon() bool
+	Result() ResultCode
+	GetState() [][]int
+	CheckPossibleMoves() []string
+	Moves() func() (Move, bool)
+	GetHint() (Move, bool)
+	AddEmptyBottle()
+	ShuffleWater()
+	GenerateRandomSolvable(maxAttempts int) error
+	PrintState()
+	Difficulty() string
+	EstimateDifficulty() DifficultyReport
+	Seed() int64
+	SetStrictMode(on bool)
+	SetLogger(Logger)
+	Subscribe(EventHandler)
+}
+
+// Solver 谜题求解器
+type Solver interface {
+	Solve() ([]Move, bool)
+}
+
+// Generator 按给定种子生成一局新游戏
+type Generator interface {
+	Generate(seed int64) Game
+}
+
+// 确保实现满足稳定接口
+var (
+	_ Game   = (*game.WaterBottleGame)(nil)
+	_ Solver = (*game.WaterBottleGame)(nil)
+)
+
+// NewGame 创建一局新游戏, 使用当前时间作为随机种子
+func NewGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool) Game {
+	return game.NewWaterBottleGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags)
+}
+
+// NewGameWithSeed 使用指定种子创建游戏
+func NewGameWithSeed(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool, seed int64) Game {
+	return game.NewWaterBottleGameWithSeed(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags, seed)
+}
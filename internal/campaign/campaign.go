@@ -0,0 +1,79 @@
+// Package campaign 内置主线战役: 一组精修关卡通过go:embed随二进制一起
+// 发布, 玩家按顺序推进, 通关进度落盘保存, 换机器也能从断点继续.
+package campaign
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/iCode-five/hello-word/internal/pack"
+	"github.com/iCode-five/hello-word/internal/storage"
+)
+
+//go:embed levels.json
+var levelsJSON []byte
+
+// progressFile 进度存档的文件名
+const progressFile = "campaign_progress.json"
+
+// progress 存档里的推进状态
+type progress struct {
+	Current int                `json:"current"` // 下一个待通关关卡的下标
+	Results []pack.LevelResult `json:"results"`
+}
+
+// Campaign 主线战役: 内置关卡加上玩家的推进进度
+type Campaign struct {
+	pack  pack.Pack
+	prog  progress
+	store storage.Storage
+}
+
+// Load 解析内置关卡并读取之前的推进进度; 没有存档时从第一关开始
+func Load(store storage.Storage) (*Campaign, error) {
+	c := &Campaign{store: store}
+	if err := json.Unmarshal(levelsJSON, &c.pack); err != nil {
+		return nil, fmt.Errorf("内置战役关卡损坏: %w", err)
+	}
+	if data, err := store.Load(progressFile); err == nil {
+		if err := json.Unmarshal(data, &c.prog); err != nil {
+			return nil, fmt.Errorf("战役进度解析失败: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// LevelCount 战役的总关数
+func (c *Campaign) LevelCount() int {
+	return len(c.pack.Levels)
+}
+
+// CompletedCount 已通关的关数
+func (c *Campaign) CompletedCount() int {
+	return c.prog.Current
+}
+
+// NextLevel 返回下一个待通关的关卡和它的下标; 全部打完时ok为false
+func (c *Campaign) NextLevel() (pack.Level, int, bool) {
+	if c.prog.Current >= len(c.pack.Levels) {
+		return pack.Level{}, 0, false
+	}
+	return c.pack.Levels[c.prog.Current], c.prog.Current, true
+}
+
+// MarkCompleted 记录一关的通关结果并推进进度, 同时落盘
+func (c *Campaign) MarkCompleted(result pack.LevelResult) error {
+	c.prog.Results = append(c.prog.Results, result)
+	c.prog.Current++
+	data, err := json.MarshalIndent(c.prog, "", "  ")
+	if err != nil {
+		return err
+	}
+	return c.store.Save(progressFile, data)
+}
+
+// Report 把已通关的逐关结果汇总成战役总结
+func (c *Campaign) Report() pack.Report {
+	return pack.BuildReport(c.pack.Name, c.prog.Results)
+}
@@ -0,0 +1,68 @@
+package campaign
+
+import (
+	"fmt"
+
+	"github.com/iCode-five/hello-word/internal/game"
+	"github.com/iCode-five/hello-word/internal/pack"
+)
+
+// 节奏规则的关键节点: 罐子和袋子各自只在一关被引入,
+// 颜色每隔固定关数加一种, 和机制引入错开.
+const (
+	jarIntroLevel  = 20
+	bagIntroLevel  = 30
+	colorEveryN    = 8
+	maxPacedColors = 8
+)
+
+// levelSpec 单关的生成参数, 战役线就是一串逐关推导出来的spec
+type levelSpec struct {
+	Colors      int
+	Empty       int
+	Capacity    int
+	Jars        int
+	JarCapacity int
+	Bags        bool
+}
+
+// bottleCount 该spec需要的瓶子总数(含空瓶)
+func (s levelSpec) bottleCount() int {
+	return s.Colors + s.Empty
+}
+
+// firstSpec 战役开局的最小配置: 两种颜色, 没有任何附加机制
+func firstSpec() levelSpec {
+	return levelSpec{Colors: 2, Empty: 2, Capacity: 4}
+}
+
+// nextSpec 按节奏规则从上一关推导下一关: 每关最多引入一个新颜色或
+// 一个新机制(罐子/袋子), 两者不会落在同一关, 难度爬升保持渐进.
+func nextSpec(prev levelSpec, idx int) levelSpec {
+	next := prev
+	switch {
+	case idx == jarIntroLevel && prev.Jars == 0:
+		next.Jars, next.JarCapacity = 1, 6
+	case idx == bagIntroLevel && !prev.Bags:
+		next.Bags = true
+	case idx%colorEveryN == 1 && prev.Colors < maxPacedColors:
+		next.Colors++
+	}
+	return next
+}
+
+// Generate 按节奏规则生成一条n关的战役线. 每关的局面由种子决定,
+// 同一个种子生成的战役完全可复现.
+func Generate(name string, n int, seed int64) pack.Pack {
+	p := pack.Pack{Name: name}
+	spec := firstSpec()
+	for i := 1; i <= n; i++ {
+		if i > 1 {
+			spec = nextSpec(spec, i)
+		}
+		g := game.NewWaterBottleGameWithSeed(spec.bottleCount(), spec.Capacity, spec.Colors,
+			spec.Empty, spec.Jars, spec.JarCapacity, spec.Bags, seed+int64(i)*37)
+		p.Levels = append(p.Levels, pack.FromGame(fmt.Sprintf("第%d关", i), g))
+	}
+	return p
+}
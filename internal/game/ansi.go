@@ -0,0 +1,47 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ANSI真彩渲染: 在支持24位色的终端里把水层画成实际的彩色块,
+// 不支持时渲染层退回表情符号. 能力探测只看环境变量, 不做termcap.
+
+// colorRGB 颜色编号对应的24位色, 下标与colorNames一致
+var colorRGB = [][3]int{
+	{0, 0, 0},       // 0 无水
+	{220, 50, 47},   // 红
+	{38, 109, 211},  // 蓝
+	{64, 160, 43},   // 绿
+	{222, 189, 43},  // 黄
+	{146, 86, 190},  // 紫
+	{226, 125, 40},  // 橙
+	{42, 161, 179},  // 青
+	{233, 120, 161}, // 粉
+	{132, 96, 60},   // 棕
+	{128, 128, 128}, // 灰
+}
+
+// TrueColorSupported 当前终端是否支持24位色: COLORTERM声明truecolor
+// 且没有被NO_COLOR禁用, TERM也不是dumb.
+func TrueColorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	ct := os.Getenv("COLORTERM")
+	return strings.Contains(ct, "truecolor") || strings.Contains(ct, "24bit")
+}
+
+// ansiCell 一格水的彩色块. 万能格画成双色块, 未知颜色退回表情.
+func ansiCell(color int) string {
+	if color == WildcardColor {
+		return "\x1b[48;2;220;50;47m \x1b[48;2;38;109;211m \x1b[0m"
+	}
+	if color <= 0 || color >= len(colorRGB) {
+		return getColorEmoji(color)
+	}
+	c := colorRGB[color]
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm  \x1b[0m", c[0], c[1], c[2])
+}
@@ -0,0 +1,52 @@
+package game
+
+import "fmt"
+
+// 搜索用的低层走子原语: 只搬水格和维护增量哈希, 不校验合法性,
+// 不记历史, 不触发事件, 也不做袋子收集. 一对严格互逆的O(层数)
+// 操作, 求解器和生成器可以applyMove-探索-unapplyMove地走, 不必
+// 每步复制整个局面. 需要完整游戏语义(收集, 金币, 胜负事件)时
+// 仍然走Pour.
+
+// ApplyMove 执行一步走子: 把m.Amount层水从m.From搬到m.To.
+// 调用方负责保证走子合法; 调试模式下做不变量检查, 违反即panic.
+func (g *WaterBottleGame) ApplyMove(m Move) {
+	if g.debug {
+		g.checkMoveInvariant(m, m.From, m.To)
+	}
+	fb, tb := g.Bottles[m.From], g.Bottles[m.To]
+	g.hash -= bottleHash(fb) + bottleHash(tb)
+	tb.Water = append(tb.Water, fb.Water[len(fb.Water)-m.Amount:]...)
+	fb.Water = fb.Water[:len(fb.Water)-m.Amount]
+	g.hash += bottleHash(fb) + bottleHash(tb)
+}
+
+// UnapplyMove 撤销一步走子: 把m.Amount层水从m.To搬回m.From.
+// 对刚执行过的同一个Move调用, 恰好还原ApplyMove前的状态(包括哈希).
+func (g *WaterBottleGame) UnapplyMove(m Move) {
+	if g.debug {
+		g.checkMoveInvariant(m, m.To, m.From)
+	}
+	fb, tb := g.Bottles[m.To], g.Bottles[m.From]
+	g.hash -= bottleHash(fb) + bottleHash(tb)
+	tb.Water = append(tb.Water, fb.Water[len(fb.Water)-m.Amount:]...)
+	fb.Water = fb.Water[:len(fb.Water)-m.Amount]
+	g.hash += bottleHash(fb) + bottleHash(tb)
+}
+
+// checkMoveInvariant 调试模式的不变量检查: src要有足够的水,
+// dst要有足够的空间. 违反说明调用方的搜索逻辑有bug, 直接panic.
+func (g *WaterBottleGame) checkMoveInvariant(m Move, src, dst int) {
+	if src < 0 || src >= len(g.Bottles) || dst < 0 || dst >= len(g.Bottles) || src == dst {
+		panic(fmt.Sprintf("ApplyMove: 非法的容器下标 %d->%d", src, dst))
+	}
+	if m.Amount < 1 {
+		panic(fmt.Sprintf("ApplyMove: 层数%d不合法", m.Amount))
+	}
+	if len(g.Bottles[src].Water) < m.Amount {
+		panic(fmt.Sprintf("ApplyMove: 容器%d只有%d层水, 搬不出%d层", src, len(g.Bottles[src].Water), m.Amount))
+	}
+	if space := g.Bottles[dst].Capacity - len(g.Bottles[dst].Water); space < m.Amount {
+		panic(fmt.Sprintf("ApplyMove: 容器%d只剩%d格空间, 装不下%d层", dst, space, m.Amount))
+	}
+}
@@ -0,0 +1,80 @@
+package game
+
+// 冷启动优化: 大盘的反向生成可能要跑好几秒. 这里先把当前对局摆成
+// 临时的已解好局面, UI立即有东西可画, 真正的谜题在一个独立的后台
+// 实例上生成, 完成后由调用方在自己的线程里换入. 引擎本身不做加锁,
+// 前后台各玩各的实例, 只通过通道交接成品.
+
+// GenerationUpdate 后台生成的进度通知.
+// Result非nil表示生成完成, 把它交给SwapGenerated换入.
+type GenerationUpdate struct {
+	Percent int
+	Result  *WaterBottleGame
+}
+
+// GenerateInBackground 立即把当前对局摆成临时已解好局面并返回,
+// 同时在后台用相同参数和种子生成真正的谜题. 返回的通道会收到若干次
+// 进度(来自生成器的真实步数上报, 不是估算), 最后一次带上成品局面,
+// 之后关闭.
+func (g *WaterBottleGame) GenerateInBackground() <-chan GenerationUpdate {
+	g.createSolvedState()
+	bottleCount, capacity, colorCount := g.BottleCount, g.Capacity, g.ColorCount
+	emptyBottles, jarCount, jarCapacity := g.EmptyBottles, g.JarCount, g.JarCapacity
+	useBags, rules, seed := g.UseBags, g.Rules, g.seed
+
+	ch := make(chan GenerationUpdate, 8)
+	go func() {
+		defer close(ch)
+		ng := newEmptyGame(bottleCount, capacity, colorCount,
+			emptyBottles, jarCount, jarCapacity, useBags, seed)
+		ng.Rules = rules
+		// 生成器按真实步数上报进度. 反向生成和验证是两个阶段,
+		// 各自从0数到total, 这里只向前不回退, 合成一条单调的百分比.
+		sent := 0
+		ng.SetProgressFunc(func(stepDone, total int) {
+			if total <= 0 {
+				return
+			}
+			pct := stepDone * 100 / total
+			if pct >= 100 {
+				pct = 99 // 100%留给带成品的最后一条
+			}
+			if pct <= sent {
+				return
+			}
+			sent = pct
+			select {
+			case ch <- GenerationUpdate{Percent: pct}:
+			default: // 调用方来不及消费就丢弃中间进度
+			}
+		})
+		ng.generateInitialState()
+		ng.SetProgressFunc(nil)
+		if useBags {
+			for _, bag := range ng.Bags {
+				if bag.Color == 0 {
+					bag.Color = ng.nextBagColor()
+				}
+			}
+		}
+		ch <- GenerationUpdate{Percent: 100, Result: ng}
+	}()
+	return ch
+}
+
+// SwapGenerated 把后台生成的成品换入当前对局: 容器, 袋子和随机源
+// 整体接管, 历史, 走子日志和步数清零, 临时局面上的操作全部作废.
+func (g *WaterBottleGame) SwapGenerated(ng *WaterBottleGame) {
+	g.Bottles = ng.Bottles
+	g.Bags = ng.Bags
+	g.collectedColors = ng.collectedColors
+	g.bagQueue = ng.bagQueue
+	g.rng = ng.rng
+	g.audit = ng.audit
+	g.history = nil
+	g.moveLog = nil
+	g.MoveCount = 0
+	g.safeCheckpoint = nil
+	g.par = 0
+	g.emit(Event{Type: EventPuzzleReady, Bottle: -1, Bag: -1})
+}
@@ -0,0 +1,48 @@
+package game
+
+import "encoding/json"
+
+// RandAuditEntry 生成过程中一次随机决策的记录
+type RandAuditEntry struct {
+	Step    int    `json:"step"`    // 第几次随机决策
+	Purpose string `json:"purpose"` // 决策用途
+	N       int    `json:"n"`       // 取值范围 [0, n)
+	Value   int    `json:"value"`   // 实际取到的值
+}
+
+// RandAuditLog 一局游戏生成阶段的完整随机决策记录.
+// 配合种子一起导出后, 任何人都可以重放验证谜题确实来自声明的种子.
+type RandAuditLog struct {
+	Seed    int64            `json:"seed"`
+	Entries []RandAuditEntry `json:"entries"`
+}
+
+// auditIntn 取一个[0,n)的随机数并记入审计日志
+func (g *WaterBottleGame) auditIntn(purpose string, n int) int {
+	v := g.rng.Intn(n)
+	g.audit.Entries = append(g.audit.Entries, RandAuditEntry{
+		Step:    len(g.audit.Entries) + 1,
+		Purpose: purpose,
+		N:       n,
+		Value:   v,
+	})
+	return v
+}
+
+// auditShuffle 洗牌并把每次交换的随机决策记入审计日志
+func (g *WaterBottleGame) auditShuffle(purpose string, n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := g.auditIntn(purpose, i+1)
+		swap(i, j)
+	}
+}
+
+// AuditLog 返回本局的随机决策审计日志
+func (g *WaterBottleGame) AuditLog() RandAuditLog {
+	return g.audit
+}
+
+// ExportAuditLog 把审计日志导出为JSON, 便于和谜题一起存档
+func (g *WaterBottleGame) ExportAuditLog() ([]byte, error) {
+	return json.MarshalIndent(g.audit, "", "  ")
+}
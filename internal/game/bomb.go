@@ -0,0 +1,132 @@
+package game
+
+import "fmt"
+
+// 炸弹水格: 带引信的水格, 必须在引信耗尽前拆除, 否则对局判负.
+// 拆除方式是把炸弹格倒到顶层同色的容器上(炸弹一次只倒一格).
+// 引信按真实步数倒数, 所有炸弹共用一根. 编码方式和冰冻格(见frozen.go)
+// 一样压在水格值里(颜色加bombOffset), 快照/存档/规范键自动覆盖.
+
+// bombOffset 炸弹水格的编码偏移, 和冰冻区间(frozenOffset起)错开
+const bombOffset = 200
+
+// isBombColor 水格值是否是炸弹编码
+func isBombColor(w int) bool {
+	return w >= bombOffset
+}
+
+// bombBase 取炸弹水格的底色; 非炸弹值原样返回
+func bombBase(w int) int {
+	if isBombColor(w) {
+		return w - bombOffset
+	}
+	return w
+}
+
+// armBomb 把颜色编码成炸弹水格
+func armBomb(c int) int {
+	return c + bombOffset
+}
+
+// layerBase 还原一个水格值的底色, 依次剥掉炸弹和冰冻编码
+func layerBase(w int) int {
+	return thawColor(bombBase(w))
+}
+
+// InjectBombs 把场上n个随机水格换成炸弹并点燃引信: fuse步之内拆完,
+// 否则判负. 选格由种子决定, 和InjectWildcards一致.
+func (g *WaterBottleGame) InjectBombs(n, fuse int) int {
+	type pos struct{ bottle, layer int }
+	var cells []pos
+	for i, b := range g.Bottles {
+		if b.Collected {
+			continue
+		}
+		for j, w := range b.Water {
+			if w != WildcardColor && !isFrozenColor(w) && !isBombColor(w) {
+				cells = append(cells, pos{i, j})
+			}
+		}
+	}
+	g.auditShuffle("炸弹:选格", len(cells), func(i, j int) {
+		cells[i], cells[j] = cells[j], cells[i]
+	})
+	if n > len(cells) {
+		n = len(cells)
+	}
+	for _, p := range cells[:n] {
+		b := g.Bottles[p.bottle]
+		g.hash -= bottleHash(b)
+		b.Water[p.layer] = armBomb(b.Water[p.layer])
+		g.hash += bottleHash(b)
+	}
+	if n > 0 {
+		g.bombFuse = fuse
+		g.logger.Logf("💣 埋了%d颗炸弹, %d步内拆完!", n, fuse)
+	}
+	return n
+}
+
+// BombFuse 引信剩余步数; 场上没有炸弹时返回0
+func (g *WaterBottleGame) BombFuse() int {
+	if !g.hasBombs() {
+		return 0
+	}
+	return g.bombFuse
+}
+
+// hasBombs 场上是否还有未拆除的炸弹
+func (g *WaterBottleGame) hasBombs() bool {
+	for _, b := range g.Bottles {
+		if b.Collected {
+			continue
+		}
+		for _, w := range b.Water {
+			if isBombColor(w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bombExploded 引信耗尽且还有炸弹没拆. 引信只会在有炸弹时从正数
+// 减到0, 所以不需要额外的"点燃"标记.
+func (g *WaterBottleGame) bombExploded() bool {
+	return g.bombFuse == 0 && g.hasBombs()
+}
+
+// tickBombFuse 每走一步引信减一. 模拟中的走子也会减(引信在快照里,
+// 回滚时一起还原), 求解器因此不会给出引信之外的"解法".
+// 拆掉最后一颗炸弹的那步不再计数, 压哨拆弹算成功.
+func (g *WaterBottleGame) tickBombFuse() {
+	if g.bombFuse <= 0 || !g.hasBombs() {
+		return
+	}
+	g.bombFuse--
+	if g.bombFuse > 0 && !g.suppressEvents {
+		g.logger.Logf("💣 引信还剩%d步", g.bombFuse)
+	}
+}
+
+// defuseLanded 一次倒水落到容器第bottom层之上后检查拆弹: 落下的
+// 炸弹格顶着同底色的水时被拆除, 变回普通水格. pourOne在搬水后调用.
+func (g *WaterBottleGame) defuseLanded(bottle, bottom int) {
+	b := g.Bottles[bottle]
+	if bottom == 0 || bottom >= len(b.Water) {
+		return
+	}
+	w := b.Water[bottom]
+	if !isBombColor(w) || layerBase(w) != layerBase(b.Water[bottom-1]) {
+		return
+	}
+	g.hash -= bottleHash(b)
+	b.Water[bottom] = bombBase(w)
+	g.hash += bottleHash(b)
+	if !g.suppressEvents {
+		g.logger.Logf("✂️ 拆除了一颗%s炸弹", getColorName(layerBase(w)))
+	}
+}
+
+// bombPourError 炸弹格的倒水限制说明
+var bombPourError = fmt.Errorf("%w: 炸弹只能倒到顶层同色的容器上", ErrColorMismatch)
@@ -0,0 +1,162 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// 机器人玩家: 自动演示, 难度校准和回归基准都需要"按某种策略把一局
+// 玩下去"的能力, 以前散落在各处各写一个循环. 这里把策略抽成Player
+// 接口, 配一个统一的RunBot执行框架; 策略只管看局面挑一步, 框架负责
+// 真正落子, 统计结果和兜底防循环.
+
+// Player 一种机器人策略
+type Player interface {
+	Name() string                               // 策略的中文名
+	ChooseMove(g *WaterBottleGame) (Move, bool) // 在当前局面挑一步, 没得可走返回false
+}
+
+// BotResult 一次机器人自动对局的结果
+type BotResult struct {
+	Bot   string // 策略名
+	Moves []Move // 实际走过的步
+	Won   bool   // 是否通关
+	Stuck bool   // 是否因无路可走/局面循环而停下
+}
+
+// RunBot 让机器人在真实对局上走到底: 通关, 无路可走, 局面重复或
+// 步数用尽为止. 走过的局面用规范键记下来, 策略再选出重复局面时
+// 直接停, 防止来回倒水死循环.
+func RunBot(g *WaterBottleGame, bot Player, maxMoves int) BotResult {
+	res := BotResult{Bot: bot.Name()}
+	seen := map[string]bool{g.CanonicalKey(): true}
+	for len(res.Moves) < maxMoves {
+		if g.IsWon() {
+			res.Won = true
+			return res
+		}
+		m, ok := bot.ChooseMove(g)
+		if !ok {
+			res.Stuck = true
+			return res
+		}
+		if _, err := g.Pour(m.From, m.To); err != nil {
+			res.Stuck = true
+			return res
+		}
+		res.Moves = append(res.Moves, m)
+		key := g.CanonicalKey()
+		if seen[key] {
+			res.Stuck = true
+			return res
+		}
+		seen[key] = true
+	}
+	res.Won = g.IsWon()
+	return res
+}
+
+// GreedyBot 贪心合并策略: 每步都挑EvaluateMove打分最高的走子,
+// 不回溯. 和"求解"演示里原来手写的循环等价但口径更讲究.
+type GreedyBot struct{}
+
+// Name 策略名
+func (GreedyBot) Name() string { return "贪心" }
+
+// ChooseMove 挑评分最高的合法走子
+func (GreedyBot) ChooseMove(g *WaterBottleGame) (Move, bool) {
+	var best Move
+	bestScore := 0
+	found := false
+	for _, m := range g.LegalMoves() {
+		score := g.EvaluateMove(m).Score
+		if !found || score > bestScore {
+			best, bestScore, found = m, score, true
+		}
+	}
+	return best, found
+}
+
+// RandomBot 均匀随机策略, 主要当基线用. 自带随机源, 不动对局的rng.
+type RandomBot struct {
+	rng *rand.Rand
+}
+
+// NewRandomBot 创建随机策略, seed为0时按当前时间
+func NewRandomBot(seed int64) *RandomBot {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &RandomBot{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Name 策略名
+func (*RandomBot) Name() string { return "随机" }
+
+// ChooseMove 均匀随机挑一步
+func (b *RandomBot) ChooseMove(g *WaterBottleGame) (Move, bool) {
+	moves := g.LegalMoves()
+	if len(moves) == 0 {
+		return Move{}, false
+	}
+	return moves[b.rng.Intn(len(moves))], true
+}
+
+// LookaheadBot 两步前瞻策略: 对每个候选走子模拟落子, 再看下一步
+// 最好能打多少分, 按"本步得分+后续最佳得分"挑. 比纯贪心少踩
+// 一步见喜两步见死的坑.
+type LookaheadBot struct{}
+
+// Name 策略名
+func (LookaheadBot) Name() string { return "前瞻" }
+
+// ChooseMove 按两步合计得分挑走子
+func (LookaheadBot) ChooseMove(g *WaterBottleGame) (Move, bool) {
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	var best Move
+	bestScore := 0
+	found := false
+	snap := g.borrowSnapshot()
+	defer releaseSnapshot(snap)
+	for _, m := range g.LegalMoves() {
+		score := g.EvaluateMove(m).Score
+		if _, err := g.Pour(m.From, m.To); err != nil {
+			continue
+		}
+		if g.IsWon() {
+			g.restoreGameState(snap)
+			return m, true
+		}
+		// 第二步取对手盘面上的最佳单步得分
+		next := 0
+		hasNext := false
+		for _, m2 := range g.LegalMoves() {
+			if s2 := g.EvaluateMove(m2).Score; !hasNext || s2 > next {
+				next, hasNext = s2, true
+			}
+		}
+		if hasNext {
+			score += next
+		}
+		g.restoreGameState(snap)
+		if !found || score > bestScore {
+			best, bestScore, found = m, score, true
+		}
+	}
+	return best, found
+}
+
+// BotByName 按名字找策略, 演示命令用. 未知名字返回nil.
+func BotByName(name string, seed int64) Player {
+	switch name {
+	case "贪心", "greedy":
+		return GreedyBot{}
+	case "随机", "random":
+		return NewRandomBot(seed)
+	case "前瞻", "lookahead":
+		return LookaheadBot{}
+	}
+	return nil
+}
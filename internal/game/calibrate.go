@@ -0,0 +1,199 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 难度校准: difficultyBand里的步数区间是拍脑袋定的, 换一套容器参数
+// 手感就对不上. 校准工具批量生成谜题, 让精确求解器量出最短解, 再让
+// 贪心bot(solveGreedy)玩一遍, 按"贪心通关率"拟合各档的步数区间 —
+// 简单档是贪心几乎都能过的, 专家档是贪心基本过不了的. 拟合出的
+// 区间表用SetDifficultyBands接回难度评估.
+
+// 各档贪心通关率的拟合目标: 累计通关率跌破阈值就切到下一档
+const (
+	calibEasyRate   = 0.8
+	calibMediumRate = 0.5
+	calibHardRate   = 0.2
+)
+
+// DifficultyBands 四个难度档各自的最短解步数区间[下界,上界]
+type DifficultyBands [4][2]int
+
+// defaultDifficultyBands 内置的步数区间, 与difficultyBand的老常量一致
+func defaultDifficultyBands() DifficultyBands {
+	return DifficultyBands{{1, 8}, {9, 16}, {17, 28}, {29, 1 << 30}}
+}
+
+// String 区间表的可读形式
+func (b DifficultyBands) String() string {
+	s := ""
+	for d := DifficultyEasy; d <= DifficultyExpert; d++ {
+		if d > DifficultyEasy {
+			s += ", "
+		}
+		if b[d][1] >= 1<<30 {
+			s += fmt.Sprintf("%s≥%d步", d, b[d][0])
+		} else {
+			s += fmt.Sprintf("%s%d~%d步", d, b[d][0], b[d][1])
+		}
+	}
+	return s
+}
+
+// SetDifficultyBands 用校准出的区间表替换内置难度区间
+func (g *WaterBottleGame) SetDifficultyBands(b DifficultyBands) {
+	g.diffBands = &b
+}
+
+// difficultyBandFor 当前生效的难度区间, 优先用校准结果
+func (g *WaterBottleGame) difficultyBandFor(d Difficulty) (min, max int) {
+	b := defaultDifficultyBands()
+	if g.diffBands != nil {
+		b = *g.diffBands
+	}
+	return b[d][0], b[d][1]
+}
+
+// calibSample 一道样本谜题的观测: 最短解步数与贪心bot是否通关
+type calibSample struct {
+	optimal int
+	greedy  bool
+}
+
+// CalibrationParams 一轮难度校准的参数
+type CalibrationParams struct {
+	Config       GameConfig // 待校准的容器配置
+	Samples      int        // 样本谜题数, 0取默认100
+	GreedyBudget int        // 贪心bot每局的步数预算, 0取默认300
+	Seed         int64      // 采样起始种子, 0按当前时间
+}
+
+// CalibrationResult 校准的统计结果与拟合出的区间表
+type CalibrationResult struct {
+	Samples     int             // 实际参与统计的样本数(求解器解出的)
+	Unsolved    int             // 求解器在上限内没解出的样本数
+	GreedyRate  float64         // 总体贪心通关率
+	RateByMoves map[int]float64 // 按最短解步数分组的贪心通关率
+	Bands       DifficultyBands // 拟合出的步数区间
+	Elapsed     time.Duration   // 校准总耗时
+}
+
+// CalibrateDifficulty 批量自玩并拟合难度区间. 每个样本换一个种子
+// 反向生成, 精确求解器给出最短解步数, 贪心bot给出"普通人水平"的
+// 通关与否; 拟合时按累计通关率找各档的切换点.
+func CalibrateDifficulty(params CalibrationParams) (CalibrationResult, error) {
+	samples := params.Samples
+	if samples <= 0 {
+		samples = 100
+	}
+	budget := params.GreedyBudget
+	if budget <= 0 {
+		budget = 300
+	}
+	seed := params.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	start := time.Now()
+	var data []calibSample
+	unsolved := 0
+	for i := 0; i < samples; i++ {
+		cfg := params.Config
+		cfg.Seed = seed + int64(i)
+		g, err := NewGame(cfg)
+		if err != nil {
+			return CalibrationResult{}, err
+		}
+		moves, ok := g.Solve()
+		if !ok {
+			unsolved++
+			continue
+		}
+		_, greedyOK := g.solveGreedy(budget)
+		data = append(data, calibSample{optimal: len(moves), greedy: greedyOK})
+	}
+	if len(data) == 0 {
+		return CalibrationResult{}, fmt.Errorf("校准失败: %d个样本求解器都没解出来", samples)
+	}
+	res := CalibrationResult{
+		Samples:     len(data),
+		Unsolved:    unsolved,
+		RateByMoves: make(map[int]float64),
+		Elapsed:     time.Since(start),
+	}
+	totalByMoves := make(map[int]int)
+	greedyByMoves := make(map[int]int)
+	greedyTotal := 0
+	for _, s := range data {
+		totalByMoves[s.optimal]++
+		if s.greedy {
+			greedyByMoves[s.optimal]++
+			greedyTotal++
+		}
+	}
+	for l, n := range totalByMoves {
+		res.RateByMoves[l] = float64(greedyByMoves[l]) / float64(n)
+	}
+	res.GreedyRate = float64(greedyTotal) / float64(len(data))
+	res.Bands = fitBands(data, totalByMoves)
+	return res, nil
+}
+
+// fitBands 按累计贪心通关率拟合区间切换点: 把样本按最短解步数
+// 从短到长扫, "不超过L步的样本"的通关率一跌破某档阈值, 该档就在
+// L-1步收口. 单个步数上的通关率噪声大, 累计口径平滑一些.
+func fitBands(data []calibSample, totalByMoves map[int]int) DifficultyBands {
+	lengths := make([]int, 0, len(totalByMoves))
+	for l := range totalByMoves {
+		lengths = append(lengths, l)
+	}
+	sort.Ints(lengths)
+	greedyByMoves := make(map[int]int)
+	for _, s := range data {
+		if s.greedy {
+			greedyByMoves[s.optimal]++
+		}
+	}
+	bands := defaultDifficultyBands()
+	cuts := [3]int{0, 0, 0} // 简单/中等/困难各档的上界
+	thresholds := [3]float64{calibEasyRate, calibMediumRate, calibHardRate}
+	cumTotal, cumGreedy := 0, 0
+	for _, l := range lengths {
+		cumTotal += totalByMoves[l]
+		cumGreedy += greedyByMoves[l]
+		rate := float64(cumGreedy) / float64(cumTotal)
+		for i := range thresholds {
+			if cuts[i] == 0 && rate < thresholds[i] {
+				cuts[i] = l - 1
+			}
+		}
+	}
+	// 没被任何样本压破阈值的档沿用内置上界; 压破了的要保证各档
+	// 区间依次衔接且非空
+	prev := 0
+	for i := 0; i < 3; i++ {
+		if cuts[i] > prev {
+			bands[i] = [2]int{prev + 1, cuts[i]}
+			prev = cuts[i]
+		} else if cuts[i] != 0 {
+			bands[i] = [2]int{prev + 1, prev + 1}
+			prev++
+		} else {
+			bands[i] = [2]int{prev + 1, maxInt(bands[i][1], prev+1)}
+			prev = bands[i][1]
+		}
+	}
+	bands[3] = [2]int{prev + 1, 1 << 30}
+	return bands
+}
+
+// maxInt 两个int的较大值
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
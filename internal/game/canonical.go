@@ -0,0 +1,58 @@
+package game
+
+import (
+	"sort"
+	"strings"
+)
+
+// 状态规范化: 把局面编码成对"等价瓶子互换"不敏感的键. 两个只差
+// 瓶子摆放顺序的局面在排序游戏里完全等价, 求解器把它们当同一个
+// 状态可以省掉大量重复搜索; 同一把钥匙也可用来查重谜题和存档.
+
+// colorByte 把颜色值编码成一个字节. 颜色从1开始, 特殊值
+// (HiddenColor/WildcardColor)是小负数, 统一偏移到非负区间.
+func colorByte(c int) byte {
+	return byte(c + 8)
+}
+
+// colorFromByte colorByte的逆变换
+func colorFromByte(b byte) int {
+	return int(b) - 8
+}
+
+// CanonicalKey 当前状态的规范键: 每个容器编码成"容量+属性+水层"的
+// 紧凑字节串, 容器间按编码排序后拼接, 袋子按原顺序附在末尾.
+// 只要两个局面互为瓶子重排, 键就相同; 容量或罐子属性不同的容器
+// 编码不同, 不会被错误合并.
+func (g *WaterBottleGame) CanonicalKey() string {
+	keys := make([]string, 0, len(g.Bottles))
+	var buf []byte
+	for _, b := range g.Bottles {
+		buf = buf[:0]
+		buf = append(buf, byte(b.Capacity))
+		var flags byte
+		if b.IsJar {
+			flags |= 1
+		}
+		if b.Collected {
+			flags |= 2
+		}
+		// 罐子变体影响行为, 变体不同的罐子不能互换
+		flags |= byte(b.Kind) << 2
+		buf = append(buf, flags)
+		for _, w := range b.Water {
+			buf = append(buf, colorByte(w))
+		}
+		keys = append(keys, string(buf))
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(0)
+	}
+	for _, bag := range g.Bags {
+		sb.WriteByte(colorByte(bag.Color))
+	}
+	return sb.String()
+}
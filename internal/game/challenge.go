@@ -0,0 +1,59 @@
+package game
+
+import "time"
+
+// 挑战模式约束: 步数上限和倒计时. 建局后按需开启, 胜负判定统一走
+// Result/IsLost, 不影响求解器和模拟(两者只看IsWon).
+
+// SetMoveLimit 设置步数上限, 0表示不限. 步数按MoveCount算,
+// 撤销退回的步数也算退回了额度.
+func (g *WaterBottleGame) SetMoveLimit(n int) {
+	g.moveLimit = n
+	if n > 0 {
+		g.logger.Logf("🚫 步数上限: %d步", n)
+	}
+}
+
+// SetTimeLimit 开启倒计时, 从调用时刻起算; d为0或负数时取消倒计时
+func (g *WaterBottleGame) SetTimeLimit(d time.Duration) {
+	if d <= 0 {
+		g.deadline = time.Time{}
+		g.timeLossNotified = false
+		return
+	}
+	g.deadline = time.Now().Add(d)
+	g.timeLossNotified = false
+	g.logger.Logf("⏰ 倒计时: %v", d)
+}
+
+// MovesLeft 剩余可用步数, 没设上限时返回-1
+func (g *WaterBottleGame) MovesLeft() int {
+	if g.moveLimit <= 0 {
+		return -1
+	}
+	if left := g.moveLimit - g.MoveCount; left > 0 {
+		return left
+	}
+	return 0
+}
+
+// TimeLeft 剩余时间, 第二个返回值表示是否开了倒计时
+func (g *WaterBottleGame) TimeLeft() (time.Duration, bool) {
+	if g.deadline.IsZero() {
+		return 0, false
+	}
+	if left := time.Until(g.deadline); left > 0 {
+		return left, true
+	}
+	return 0, true
+}
+
+// timedOut 倒计时是否已耗尽
+func (g *WaterBottleGame) timedOut() bool {
+	return !g.deadline.IsZero() && time.Now().After(g.deadline)
+}
+
+// IsLost 当前对局是否已经失败(死局/超时/步数用尽), 和IsWon对偶
+func (g *WaterBottleGame) IsLost() bool {
+	return g.Result().IsLose()
+}
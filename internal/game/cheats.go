@@ -0,0 +1,120 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDebugOnly 作弊接口只在调试模式下可用
+var ErrDebugOnly = errors.New("作弊命令只在调试模式下可用")
+
+// SetDebug 打开或关闭调试模式. 作弊接口用于测试战役脚本和UI边界情况,
+// 正常对局里保持关闭.
+func (g *WaterBottleGame) SetDebug(on bool) {
+	g.debug = on
+}
+
+// CheatRevealSolution 直接返回求解器找到的完整解法
+func (g *WaterBottleGame) CheatRevealSolution() ([]Move, error) {
+	if !g.debug {
+		return nil, ErrDebugOnly
+	}
+	moves, ok := g.Solve()
+	if !ok {
+		return nil, fmt.Errorf("求解器在状态上限内没有找到解")
+	}
+	return moves, nil
+}
+
+// CheatTeleportUnit 无视颜色规则, 把from瓶口的一层水直接搬到to瓶口.
+// 走历史快照, 可以用撤销恢复.
+func (g *WaterBottleGame) CheatTeleportUnit(from, to int) error {
+	if !g.debug {
+		return ErrDebugOnly
+	}
+	if from < 0 || from >= len(g.Bottles) || to < 0 || to >= len(g.Bottles) || from == to {
+		return ErrInvalidIndex
+	}
+	fb, tb := g.Bottles[from], g.Bottles[to]
+	if fb.IsEmpty() {
+		return ErrSourceEmpty
+	}
+	if tb.IsFull() {
+		return ErrTargetFull
+	}
+	g.pushHistory()
+	c := fb.TopColor()
+	fb.Water = fb.Water[:len(fb.Water)-1]
+	tb.Water = append(tb.Water, c)
+	return nil
+}
+
+// CheatCompleteBottle 强行完成一个瓶子: 把它瓶口颜色的水从全场聚过来
+// 装满它, 腾出来的其他颜色塞进任何有空间的容器. 空间不够时报错且不改动.
+func (g *WaterBottleGame) CheatCompleteBottle(index int) error {
+	if !g.debug {
+		return ErrDebugOnly
+	}
+	if index < 0 || index >= len(g.Bottles) {
+		return ErrInvalidIndex
+	}
+	b := g.Bottles[index]
+	if b.IsEmpty() {
+		return ErrSourceEmpty
+	}
+	c := b.TopColor()
+	g.pushHistory()
+	// 把目标颜色全部取出, 其余水记为待安置
+	var displaced []int
+	total := 0
+	for _, other := range g.Bottles {
+		if other.Collected {
+			continue
+		}
+		var kept []int
+		for _, w := range other.Water {
+			if w == c {
+				total++
+			} else if other == b {
+				displaced = append(displaced, w)
+			} else {
+				kept = append(kept, w)
+			}
+		}
+		if other == b {
+			other.Water = other.Water[:0]
+		} else {
+			other.Water = kept
+		}
+	}
+	if total < b.Capacity {
+		g.Undo()
+		return fmt.Errorf("场上%s只有%d格, 装不满容量%d的瓶子", getColorName(c), total, b.Capacity)
+	}
+	// 多余的目标颜色也要安置
+	for i := 0; i < total-b.Capacity; i++ {
+		displaced = append(displaced, c)
+	}
+	for i := 0; i < b.Capacity; i++ {
+		b.Water = append(b.Water, c)
+	}
+	for _, w := range displaced {
+		placed := false
+		for _, other := range g.Bottles {
+			if other == b || other.Collected || other.IsFull() {
+				continue
+			}
+			other.Water = append(other.Water, w)
+			placed = true
+			break
+		}
+		if !placed {
+			g.Undo()
+			return fmt.Errorf("其他容器空间不足, 无法安置腾出来的水")
+		}
+	}
+	if g.UseBags {
+		g.checkBagCollection()
+	}
+	return nil
+}
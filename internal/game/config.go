@@ -0,0 +1,131 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GameConfig 一局游戏的完整配置. NewWaterBottleGame的参数列表已经长到
+// 不好再加了, 新代码应该改用NewGame(cfg); 老构造函数保留, 等价于
+// 填好对应字段的配置. 零值字段取默认(见normalize), 所以调用方只需要
+// 写自己关心的选项.
+type GameConfig struct {
+	BottleCount  int            `json:"bottleCount"`           // 普通瓶子数量, 0取默认6
+	Capacity     int            `json:"capacity"`              // 瓶子容量, 0取默认4
+	ColorCount   int            `json:"colorCount"`            // 颜色数量, 0取默认4
+	EmptyBottles int            `json:"emptyBottles"`          // 空瓶数量, 0取默认2
+	JarCount     int            `json:"jarCount"`              // 罐子数量
+	JarCapacity  int            `json:"jarCapacity"`           // 罐子容量, 用罐子且为0时取瓶子容量+2
+	UseBags      bool           `json:"useBags"`               // 袋子模式
+	Rules        RuleSet        `json:"rules,omitempty"`       // 规则变体, 零值是经典倒水
+	StrictMode   bool           `json:"strictMode,omitempty"`  // 严格模式
+	Mystery      bool           `json:"mystery,omitempty"`     // 隐藏层模式
+	Wildcards    int            `json:"wildcards,omitempty"`   // 万能色格数
+	Seed         int64          `json:"seed,omitempty"`        // 随机种子, 0表示按当前时间
+	GenRetries   int            `json:"genRetries,omitempty"`  // 生成重试上限, 0取默认, 见generation.go
+	MoveLimit    int            `json:"moveLimit,omitempty"`   // 步数上限, 0表示不限
+	TimeLimit    time.Duration  `json:"timeLimit,omitempty"`   // 倒计时时长, 0表示不限
+	EconomyMode  bool           `json:"economyMode,omitempty"` // 经济模式, 见economy.go
+	Scoring      *ScoringConfig `json:"scoring,omitempty"`     // 计分配置, nil取默认
+	Win          WinCondition   `json:"-"`                     // 胜利条件插件, nil用默认
+}
+
+// DefaultConfig 常规的6瓶4色2空配置
+func DefaultConfig() GameConfig {
+	return GameConfig{BottleCount: 6, Capacity: 4, ColorCount: 4, EmptyBottles: 2}
+}
+
+// normalize 把零值字段换成默认值, 返回补全后的副本
+func (cfg GameConfig) normalize() GameConfig {
+	def := DefaultConfig()
+	if cfg.BottleCount == 0 {
+		cfg.BottleCount = def.BottleCount
+	}
+	if cfg.Capacity == 0 {
+		cfg.Capacity = def.Capacity
+	}
+	if cfg.ColorCount == 0 {
+		cfg.ColorCount = def.ColorCount
+	}
+	if cfg.EmptyBottles == 0 {
+		cfg.EmptyBottles = def.EmptyBottles
+	}
+	if cfg.JarCount > 0 && cfg.JarCapacity == 0 {
+		cfg.JarCapacity = cfg.Capacity + 2
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	return cfg
+}
+
+// Validate 校验补全后的整套配置. 容器参数复用ValidateParams的规则,
+// 这里再补上限制类和变体类字段的检查.
+func (cfg GameConfig) Validate() error {
+	cfg = cfg.normalize()
+	v := ValidateParams(cfg.BottleCount, cfg.Capacity, cfg.ColorCount,
+		cfg.EmptyBottles, cfg.JarCount, cfg.JarCapacity)
+	var msgs []string
+	for _, issue := range v.Issues {
+		msgs = append(msgs, issue.Message)
+	}
+	if cfg.Rules != RuleWaterSort && cfg.Rules != RuleBallSort {
+		msgs = append(msgs, fmt.Sprintf("未知的规则变体: %d", cfg.Rules))
+	}
+	if cfg.Wildcards < 0 {
+		msgs = append(msgs, "万能色格数不能是负数")
+	}
+	if cfg.MoveLimit < 0 {
+		msgs = append(msgs, "步数上限不能是负数")
+	}
+	if cfg.TimeLimit < 0 {
+		msgs = append(msgs, "倒计时时长不能是负数")
+	}
+	if cfg.GenRetries < 0 {
+		msgs = append(msgs, "生成重试上限不能是负数")
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("配置不合法: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// NewGame 按配置创建一局新游戏, 配置整体校验通过才开始生成.
+func NewGame(cfg GameConfig) (*WaterBottleGame, error) {
+	cfg = cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	// 初始生成要吃到重试上限, 所以不走NewWaterBottleGameWithSeed,
+	// 而是先建空对局, 配好上限再生成
+	g := newEmptyGame(cfg.BottleCount, cfg.Capacity, cfg.ColorCount,
+		cfg.EmptyBottles, cfg.JarCount, cfg.JarCapacity, cfg.UseBags, cfg.Seed)
+	g.SetGenerationRetries(cfg.GenRetries)
+	g.generateInitialState()
+	if cfg.UseBags {
+		for _, bag := range g.Bags {
+			if bag.Color == 0 {
+				bag.Color = g.nextBagColor()
+			}
+		}
+	}
+	g.SetRuleSet(cfg.Rules)
+	g.SetStrictMode(cfg.StrictMode)
+	if cfg.Mystery {
+		g.SetMysteryMode(true)
+	}
+	if cfg.Wildcards > 0 {
+		g.InjectWildcards(cfg.Wildcards)
+	}
+	g.SetMoveLimit(cfg.MoveLimit)
+	if cfg.TimeLimit > 0 {
+		g.SetTimeLimit(cfg.TimeLimit)
+	}
+	g.SetEconomyMode(cfg.EconomyMode)
+	if cfg.Scoring != nil {
+		g.SetScoringConfig(*cfg.Scoring)
+	}
+	g.SetWinCondition(cfg.Win)
+	return g, nil
+}
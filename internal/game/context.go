@@ -0,0 +1,50 @@
+package game
+
+import (
+	"context"
+	"errors"
+)
+
+// 长时操作的取消支持: 反向生成和求解在大盘上可能跑很久, 服务端
+// 需要能限时/中止. 带Context的入口把ctx挂在对局上, 生成和搜索的
+// 热循环每轮检查一次, 取消后立刻收手并把ctx.Err()原样返回.
+
+// ErrNoSolution 求解器在预算内没有找到解
+var ErrNoSolution = errors.New("求解器没有找到解")
+
+// cancelled 当前挂着的Context是否已取消, 没挂Context时恒为nil
+func (g *WaterBottleGame) cancelled() error {
+	if g.ctx == nil {
+		return nil
+	}
+	select {
+	case <-g.ctx.Done():
+		return g.ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// SolveContext 带取消的求解: ctx取消或超时后及时返回ctx.Err(),
+// 预算内没有解时返回ErrNoSolution, 其余和Solve一致.
+func (g *WaterBottleGame) SolveContext(ctx context.Context) ([]Move, error) {
+	g.ctx = ctx
+	defer func() { g.ctx = nil }()
+	path, ok := g.Solve()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoSolution
+	}
+	return path, nil
+}
+
+// GenerateByReverseContext 带取消的反向生成. 取消后返回ctx.Err(),
+// 此时局面处于生成中途, 调用方应当丢弃这局.
+func (g *WaterBottleGame) GenerateByReverseContext(ctx context.Context, steps int) error {
+	g.ctx = ctx
+	defer func() { g.ctx = nil }()
+	g.GenerateByReverse(steps)
+	return ctx.Err()
+}
@@ -0,0 +1,58 @@
+package game
+
+// 解法计数: 关卡设计师关心一个谜题是"一条道走到黑"还是局面开阔.
+// 这里做穷举型的路径计数, 只适合小盘, 数到上限就收手.
+
+// maxSolutionStates 解法计数的展开预算, 防止在开阔局面上跑飞
+const maxSolutionStates = 200000
+
+// CountSolutions 统计从当前局面出发互不相同的解法路径数, 最多数到
+// limit. 路径不允许经过重复局面(否则绕圈会产生无穷条路径); 两条
+// 路径只要有一步不同就算不同的解. 预算耗尽时返回已数到的数量.
+func (g *WaterBottleGame) CountSolutions(limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	start := g.borrowSnapshot()
+	defer func() {
+		g.restoreGameState(start)
+		releaseSnapshot(start)
+	}()
+	g.rebuildHash()
+
+	count, explored := 0, 0
+	onPath := make(map[uint64]bool)
+	var dfs func()
+	dfs = func() {
+		if count >= limit || explored >= maxSolutionStates || g.cancelled() != nil {
+			return
+		}
+		explored++
+		if g.IsWon() {
+			count++
+			return
+		}
+		if onPath[g.hash] {
+			return
+		}
+		onPath[g.hash] = true
+		here := g.hash
+		snap := g.borrowSnapshot()
+		for _, m := range g.LegalMoves() {
+			if _, err := g.Pour(m.From, m.To); err == nil {
+				dfs()
+			}
+			g.restoreGameState(snap)
+			if count >= limit {
+				break
+			}
+		}
+		releaseSnapshot(snap)
+		delete(onPath, here)
+	}
+	dfs()
+	return count
+}
@@ -0,0 +1,18 @@
+package game
+
+import "time"
+
+// 每日谜题: 全球玩家同一天拿到同一个谜题. 种子由UTC日期确定性推出,
+// 和机器, 时区设置无关; 参数相同的情况下同一天必然生成同一局.
+
+// DailySeed 由日期推出的确定性种子, 按UTC的年月日计算
+func DailySeed(date time.Time) int64 {
+	d := date.UTC()
+	return int64(zobristMix(uint64(d.Year()*10000 + int(d.Month())*100 + d.Day())))
+}
+
+// NewDailyPuzzle 创建指定日期的每日谜题, 参数含义与NewWaterBottleGame一致
+func NewDailyPuzzle(date time.Time, bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool) *WaterBottleGame {
+	return NewWaterBottleGameWithSeed(bottleCount, capacity, colorCount,
+		emptyBottles, jarCount, jarCapacity, useBags, DailySeed(date))
+}
@@ -0,0 +1,148 @@
+package game
+
+import "fmt"
+
+// Difficulty 难度等级
+type Difficulty int
+
+const (
+	DifficultyEasy   Difficulty = iota // 简单
+	DifficultyMedium                   // 中等
+	DifficultyHard                     // 困难
+	DifficultyExpert                   // 专家
+)
+
+// String 返回难度的中文名称
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "简单"
+	case DifficultyMedium:
+		return "中等"
+	case DifficultyHard:
+		return "困难"
+	case DifficultyExpert:
+		return "专家"
+	}
+	return "未知"
+}
+
+// DifficultyReport 基于求解器搜索的难度报告.
+// 旧的calculateDifficulty只看瓶子数*颜色数*容量, 和真实手感相差很远;
+// 这里直接统计搜索树的特征.
+type DifficultyReport struct {
+	Solvable        bool       // 求解器是否在状态上限内找到解
+	OptimalMoves    int        // 最短解步数(在搜索上限内)
+	BranchingFactor float64    // 平均每个状态的可行分支数
+	DeadEnds        int        // 搜索中遇到的死局状态数
+	Level           Difficulty // 综合难度等级
+}
+
+// EstimateDifficulty 运行求解器并统计搜索特征, 给出难度报告
+func (g *WaterBottleGame) EstimateDifficulty() DifficultyReport {
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+
+	type node struct {
+		snap  *gameSnapshot
+		depth int
+	}
+	report := DifficultyReport{}
+	visited := make(map[string]bool)
+	queue := []node{{snap: start}}
+	explored := 0
+	totalBranches := 0
+	for len(queue) > 0 && explored < maxSolverStates {
+		cur := queue[0]
+		queue = queue[1:]
+		g.restoreGameState(cur.snap)
+		if g.IsWon() {
+			if !report.Solvable {
+				report.Solvable = true
+				report.OptimalMoves = cur.depth
+			}
+			continue
+		}
+		sig := g.CanonicalKey()
+		if visited[sig] {
+			continue
+		}
+		visited[sig] = true
+		explored++
+		branches := 0
+		for from := 0; from < len(g.Bottles); from++ {
+			for to := 0; to < len(g.Bottles); to++ {
+				if from == to {
+					continue
+				}
+				g.restoreGameState(cur.snap)
+				if _, err := g.Pour(from, to); err == nil {
+					branches++
+					if !report.Solvable {
+						queue = append(queue, node{snap: g.copyGameState(), depth: cur.depth + 1})
+					}
+				}
+			}
+		}
+		totalBranches += branches
+		if branches == 0 {
+			report.DeadEnds++
+		}
+		if report.Solvable {
+			break
+		}
+	}
+	if explored > 0 {
+		report.BranchingFactor = float64(totalBranches) / float64(explored)
+	}
+	report.Level = g.difficultyLevel(report)
+	return report
+}
+
+// difficultyBand 每个难度等级对应的最短解步数区间(内置值).
+// 对局可以用SetDifficultyBands换成校准结果, 见calibrate.go.
+func difficultyBand(d Difficulty) (min, max int) {
+	b := defaultDifficultyBands()
+	return b[d][0], b[d][1]
+}
+
+// GeneratePuzzleWithDifficulty 反复反向生成并用求解器评分, 直到最短解
+// 步数落在目标难度的区间内. 关卡设计可以据此要求"一道25~35步的谜题".
+// 超过maxAttempts次仍未命中时返回错误, 局面保留最后一次尝试.
+func (g *WaterBottleGame) GeneratePuzzleWithDifficulty(target Difficulty, maxAttempts int) error {
+	min, max := g.difficultyBandFor(target)
+	steps := g.BottleCount * g.Capacity * 2
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		g.generateByReverseWithSteps(steps)
+		r := g.EstimateDifficulty()
+		if r.Solvable && r.OptimalMoves >= min && r.OptimalMoves <= max {
+			g.logger.Logf("✅ 生成%s难度谜题 (第%d次尝试, 最短%d步)", target, attempt, r.OptimalMoves)
+			return nil
+		}
+		g.logger.Logf("⚠️ 第%d次生成难度不符 (最短%d步, 目标%d~%d), 重试", attempt, r.OptimalMoves, min, max)
+	}
+	return fmt.Errorf("生成%d次后仍未得到%s难度的谜题", maxAttempts, target)
+}
+
+// difficultyLevel 把搜索特征映射为难度等级, 步数区间可被校准覆盖
+func (g *WaterBottleGame) difficultyLevel(r DifficultyReport) Difficulty {
+	if !r.Solvable {
+		// 在搜索上限内都找不到解, 至少是专家级
+		return DifficultyExpert
+	}
+	level := DifficultyExpert
+	for d := DifficultyEasy; d <= DifficultyHard; d++ {
+		if _, max := g.difficultyBandFor(d); r.OptimalMoves <= max {
+			level = d
+			break
+		}
+	}
+	// 死胡同很多的谜题比步数相同的普通谜题更折磨人
+	if r.DeadEnds >= 20 && level < DifficultyExpert {
+		level++
+	}
+	return level
+}
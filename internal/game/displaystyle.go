@@ -0,0 +1,43 @@
+package game
+
+// 色盲友好显示: 纯表情的水层对色盲玩家不可辨, 这里提供字母和
+// 纹理两种替代样式, 每种颜色对应一个独一无二的字符.
+
+// DisplayStyle 水层的显示样式
+type DisplayStyle int
+
+const (
+	// StyleEmoji 彩色表情, 默认样式
+	StyleEmoji DisplayStyle = iota
+	// StyleLetters 每种颜色一个字母 (R/B/G/...)
+	StyleLetters
+	// StylePatterns 每种颜色一种纹理块 (░/▒/▓/...)
+	StylePatterns
+)
+
+// colorLetters 颜色编号对应的字母, 下标与colorNames一致
+var colorLetters = []string{" ", "R", "B", "G", "Y", "P", "O", "C", "K", "N", "A"}
+
+// colorPatterns 颜色编号对应的纹理块, 下标与colorNames一致
+var colorPatterns = []string{" ", "█", "░", "▒", "▓", "▞", "▚", "▙", "▜", "▛", "▟"}
+
+// styledCell 按样式渲染一格水, 样式表覆盖不到的颜色退回表情
+func styledCell(color int, style DisplayStyle) string {
+	if color == WildcardColor {
+		return "*"
+	}
+	table := colorLetters
+	if style == StylePatterns {
+		table = colorPatterns
+	}
+	if color <= 0 || color >= len(table) {
+		return getColorEmoji(color)
+	}
+	return table[color]
+}
+
+// SetDisplayStyle 设置本局的默认显示样式, PrintState和未显式指定
+// 样式的Render都会采用
+func (g *WaterBottleGame) SetDisplayStyle(style DisplayStyle) {
+	g.displayStyle = style
+}
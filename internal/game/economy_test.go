@@ -0,0 +1,80 @@
+package game
+
+import "testing"
+
+// nearWinGame 构造只差一步就通关的两色小局
+func nearWinGame(t *testing.T) *WaterBottleGame {
+	t.Helper()
+	g, err := NewWaterBottleGameFromState(3, 2, 2, 1, 0, 0, false, 1,
+		[][]int{{1, 1}, {2}, {2}})
+	if err != nil {
+		t.Fatalf("构造局面失败: %v", err)
+	}
+	return g
+}
+
+// TestWinRewardPaidOnce 经济模式通关发奖励, 且一局只发一次
+func TestWinRewardPaidOnce(t *testing.T) {
+	g := nearWinGame(t)
+	g.SetEconomyMode(true)
+	if _, err := g.Pour(2, 1); err != nil {
+		t.Fatalf("通关走子失败: %v", err)
+	}
+	if !g.IsWon() {
+		t.Fatalf("倒完应该通关")
+	}
+	want := g.WinReward() + CoinsPerBottleCompleted
+	if g.Coins() != want {
+		t.Errorf("通关应有奖励%d金币, 余额%d", want, g.Coins())
+	}
+	// 回撤(金币随快照回滚)再通关, 通关奖励不能再发一次,
+	// 只会重新拿到倒满瓶子的小奖励
+	g.Undo()
+	if _, err := g.Pour(2, 1); err != nil {
+		t.Fatalf("重新通关失败: %v", err)
+	}
+	if g.Coins() != CoinsPerBottleCompleted {
+		t.Errorf("重复通关不应再发通关奖励, 余额%d", g.Coins())
+	}
+}
+
+// TestHintCostsCoins 经济模式下提示扣费, 没钱时拿不到提示
+func TestHintCostsCoins(t *testing.T) {
+	g := nearWinGame(t)
+	g.SetEconomyMode(true)
+	if _, ok := g.GetHint(); ok {
+		t.Errorf("没钱时不应拿到提示")
+	}
+	g.AddCoins(HintCost)
+	if _, ok := g.GetHint(); !ok {
+		t.Errorf("有钱且有解时应拿到提示")
+	}
+	if g.Coins() != 0 {
+		t.Errorf("提示应扣掉%d金币, 余额还剩%d", HintCost, g.Coins())
+	}
+}
+
+// TestPurchaseJar 买罐子: 容量和数量有上限, 没钱要报错
+func TestPurchaseJar(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 0, 0, false, 5)
+	g.SetEconomyMode(true)
+	if err := g.PurchaseJar(1); err == nil {
+		t.Errorf("容量越界应报错")
+	}
+	if err := g.PurchaseJar(4); err == nil {
+		t.Errorf("没钱买罐子应报错")
+	}
+	g.AddCoins(JarCost(4) * 10)
+	for i := 0; i < g.BottleCount/2; i++ {
+		if err := g.PurchaseJar(4); err != nil {
+			t.Fatalf("第%d个罐子应买成: %v", i+1, err)
+		}
+	}
+	// 罐子数到了瓶子数一半的上限, 再买必须被拒
+	if err := g.PurchaseJar(4); err == nil {
+		t.Errorf("超过罐子数量上限应报错")
+	}
+	if g.JarCount != g.BottleCount/2 {
+		t.Errorf("罐子数应停在上限%d, 实际%d", g.BottleCount/2, g.JarCount)
+	}
+}
@@ -0,0 +1,44 @@
+package game
+
+// 无尽生存模式: 每隔几步场上就多出一个装了杂水的新瓶子, 容器数量
+// 到上限后不再增加. 没有"通关"的概念(把场面整理干净当然也算赢),
+// 目标是在死局前尽量多完成瓶子, 成绩看GameStats.Completed.
+
+// SetEndlessMode 开启生存模式: 每every步追加一个新瓶, 容器总数
+// 不超过maxBottles. every<=0时关闭. 新瓶的杂水由本局随机源决定.
+func (g *WaterBottleGame) SetEndlessMode(every, maxBottles int) {
+	g.endlessEvery = every
+	g.endlessCap = maxBottles
+}
+
+// EndlessMode 生存模式是否开启
+func (g *WaterBottleGame) EndlessMode() bool {
+	return g.endlessEvery > 0
+}
+
+// maybeSpawnGarbage 真实走子后检查是否该上新瓶. 模拟走子不触发
+// (finishTurn只在非模拟路径调用它), 求解器看到的是静态局面.
+func (g *WaterBottleGame) maybeSpawnGarbage() {
+	if g.endlessEvery <= 0 || g.MoveCount == 0 || g.MoveCount%g.endlessEvery != 0 {
+		return
+	}
+	if len(g.Bottles) >= g.endlessCap {
+		return
+	}
+	nb := &Bottle{Capacity: g.Capacity}
+	fill := 1
+	if g.Capacity > 2 {
+		fill += g.rng.Intn(g.Capacity - 1)
+	}
+	for k := 0; k < fill; k++ {
+		nb.Water = append(nb.Water, 1+g.rng.Intn(g.ColorCount))
+	}
+	// 和AddEmptyBottle一样插在罐子前面, 不进撤销历史
+	bottles := append([]*Bottle(nil), g.Bottles[:g.BottleCount]...)
+	bottles = append(bottles, nb)
+	bottles = append(bottles, g.Bottles[g.BottleCount:]...)
+	g.Bottles = bottles
+	g.BottleCount++
+	g.hash += bottleHash(nb)
+	g.logger.Logf("🗑️ 来了一个装了%d层杂水的新瓶子 (共%d个容器)", fill, len(g.Bottles))
+}
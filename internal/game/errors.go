@@ -0,0 +1,15 @@
+package game
+
+import "errors"
+
+// 倒水失败的类型化错误. 调用方用errors.Is匹配, 不要去解析错误文案.
+var (
+	ErrInvalidIndex     = errors.New("瓶子编号超出范围")
+	ErrSameBottle       = errors.New("不能倒给自己")
+	ErrCollectedBottle  = errors.New("瓶子已被袋子收集")
+	ErrSourceEmpty      = errors.New("源瓶是空的")
+	ErrTargetFull       = errors.New("目标瓶已满")
+	ErrColorMismatch    = errors.New("颜色不匹配")
+	ErrStrictModeLocked = errors.New("严格模式下不能拆开已完成的瓶子")
+	ErrInvalidAmount    = errors.New("倒水层数不合法")
+)
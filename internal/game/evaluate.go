@@ -0,0 +1,74 @@
+package game
+
+import "fmt"
+
+// 走子点评: 给教学/辅助界面用的"这步棋好在哪"分析. 评分只看一步
+// 模拟后的局面变化, 不做深搜, 所以是启发式参考而不是最优判断.
+
+// MoveEvaluation 一步走子的点评
+type MoveEvaluation struct {
+	Move    Move
+	Legal   bool     // 这步是否合法
+	Score   int      // 启发式评分, 越高越好; 不合法时无意义
+	Reasons []string // 人类可读的理由, 按重要程度排列
+}
+
+// EvaluateMove 点评一步走子: 模拟执行后比较局面的启发式评分,
+// 并把能讲清楚的变化(凑齐颜色, 倒空瓶子, 浪费空瓶, 走向死局)
+// 翻译成文字理由. 不改变对局状态.
+func (g *WaterBottleGame) EvaluateMove(m Move) MoveEvaluation {
+	ev := MoveEvaluation{Move: m}
+	if reason := g.GetPourFailureReason(m.From, m.To); reason != "" {
+		ev.Reasons = append(ev.Reasons, "不合法: "+reason)
+		return ev
+	}
+	ev.Legal = true
+
+	fb, tb := g.Bottles[m.From], g.Bottles[m.To]
+	targetWasEmpty := tb.IsEmpty()
+	sourceRun := fb.TopRunLength()
+	sourceSingle := isSingleColor(fb)
+
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	snap := g.borrowSnapshot()
+	before := g.stateScore()
+	_, err := g.Pour(m.From, m.To)
+	after := g.stateScore()
+	completed := err == nil && (tb.Collected ||
+		(!tb.IsEmpty() && len(tb.Water) == tb.Capacity && isSingleColor(tb)))
+	emptied := err == nil && fb.IsEmpty()
+	dead := err == nil && !g.IsWon() && !g.hasLegalMove()
+	g.restoreGameState(snap)
+	releaseSnapshot(snap)
+	g.suppressEvents = prev
+
+	// stateScore越小越好, 这里翻转成越高越好
+	ev.Score = before - after
+	if completed {
+		ev.Score += 3
+		ev.Reasons = append(ev.Reasons, "凑齐了一整瓶同色, 离胜利更近")
+	}
+	if emptied {
+		ev.Score += 2
+		ev.Reasons = append(ev.Reasons, fmt.Sprintf("倒空了%s, 多出一个周转空间", g.ContainerName(m.From)))
+	}
+	if targetWasEmpty && sourceSingle && sourceRun < len(fb.Water) {
+		ev.Score -= 2
+		ev.Reasons = append(ev.Reasons, "把单色瓶拆进空瓶, 白白占用了一个空瓶")
+	} else if targetWasEmpty && !emptied {
+		ev.Score--
+		ev.Reasons = append(ev.Reasons, "占用了一个空瓶但没倒空源瓶")
+	}
+	if ev.Score > 0 && len(ev.Reasons) == 0 {
+		ev.Reasons = append(ev.Reasons, "同色合并, 局面更整齐了")
+	}
+	if dead {
+		ev.Score -= 10
+		ev.Reasons = append(ev.Reasons, "走完这步就没有任何可行操作了, 会陷入死局")
+	}
+	if len(ev.Reasons) == 0 {
+		ev.Reasons = append(ev.Reasons, "中性的一步, 局面没有明显变化")
+	}
+	return ev
+}
@@ -0,0 +1,75 @@
+package game
+
+// EventType 引擎事件类型
+type EventType int
+
+const (
+	EventPourPerformed        EventType = iota // 完成了一次倒水
+	EventBottleCompleted                       // 某个瓶子被装满单色水
+	EventBottleCollectedByBag                  // 袋子收集了一个瓶子
+	EventBagColorChanged                       // 袋子换了需要收集的颜色
+	EventDeadlock                              // 没有任何可行操作
+	EventWon                                   // 获胜
+	EventPuzzleReady                           // 后台生成的谜题已换入(见async.go)
+	EventMoveLimitReached                      // 步数上限耗尽(见challenge.go)
+	EventTimeExpired                           // 倒计时耗尽(见challenge.go)
+	EventScoreChanged                          // 分数发生变化(见score.go)
+	EventBombExploded                          // 炸弹引信耗尽(见bomb.go)
+)
+
+// String 返回事件类型名称
+func (t EventType) String() string {
+	switch t {
+	case EventPourPerformed:
+		return "PourPerformed"
+	case EventBottleCompleted:
+		return "BottleCompleted"
+	case EventBottleCollectedByBag:
+		return "BottleCollectedByBag"
+	case EventBagColorChanged:
+		return "BagColorChanged"
+	case EventDeadlock:
+		return "Deadlock"
+	case EventWon:
+		return "Won"
+	case EventPuzzleReady:
+		return "PuzzleReady"
+	case EventMoveLimitReached:
+		return "MoveLimitReached"
+	case EventTimeExpired:
+		return "TimeExpired"
+	case EventScoreChanged:
+		return "ScoreChanged"
+	case EventBombExploded:
+		return "BombExploded"
+	}
+	return "Unknown"
+}
+
+// Event 引擎发出的一条事件
+type Event struct {
+	Type   EventType
+	Move   Move // EventPourPerformed时有效
+	Bottle int  // 相关瓶子下标, 无关时为-1
+	Bag    int  // 相关袋子下标, 无关时为-1
+	Color  int  // 相关颜色, 无关时为0
+	Score  int  // EventScoreChanged时的最新分数
+}
+
+// EventHandler 事件回调
+type EventHandler func(Event)
+
+// Subscribe 注册事件回调. UI和统计系统可以订阅事件而不必解析文本输出.
+func (g *WaterBottleGame) Subscribe(h EventHandler) {
+	g.handlers = append(g.handlers, h)
+}
+
+// emit 分发事件给所有订阅者; 模拟过程(求解/枚举可行步/生成)不发事件
+func (g *WaterBottleGame) emit(e Event) {
+	if g.suppressEvents {
+		return
+	}
+	for _, h := range g.handlers {
+		h(e)
+	}
+}
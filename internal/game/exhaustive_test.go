@@ -0,0 +1,97 @@
+package game
+
+import "testing"
+
+// exhaustSearch 测试支撑: 从当前局面穷举整个可达状态空间(不设求解器
+// 那样的预算上限, 只留一个防爆炸的安全限额), 对每个状态调用check,
+// 返回访问的状态数和到最近胜利状态的最短步数(不可解时为-1).
+// 只适合微型盘, 状态数超过limit直接Fatal.
+func exhaustSearch(t *testing.T, g *WaterBottleGame, limit int, check func()) (int, int) {
+	t.Helper()
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+
+	type node struct {
+		snap *gameSnapshot
+		dist int
+	}
+	visited := make(map[string]bool)
+	queue := []node{{snap: start}}
+	winDist := -1
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		g.restoreGameState(cur.snap)
+		sig := g.CanonicalKey()
+		if visited[sig] {
+			continue
+		}
+		visited[sig] = true
+		if len(visited) > limit {
+			t.Fatalf("状态空间超过限额%d, 这个配置不算微型盘", limit)
+		}
+		check()
+		if g.IsWon() && winDist < 0 {
+			winDist = cur.dist
+		}
+		for from := 0; from < len(g.Bottles); from++ {
+			for to := 0; to < len(g.Bottles); to++ {
+				if from == to {
+					continue
+				}
+				g.restoreGameState(cur.snap)
+				if _, err := g.Pour(from, to); err == nil {
+					queue = append(queue, node{snap: g.copyGameState(), dist: cur.dist + 1})
+				}
+			}
+		}
+	}
+	return len(visited), winDist
+}
+
+// colorCensus 统计每种颜色在场上的水量(含已收集的瓶子按0算)
+func colorCensus(g *WaterBottleGame) map[int]int {
+	census := make(map[int]int)
+	for _, b := range g.Bottles {
+		for _, w := range b.Water {
+			census[w]++
+		}
+	}
+	return census
+}
+
+// TestExhaustiveTinyBoardProofs 微型盘上的穷举证明: 整个可达状态空间
+// 被枚举一遍, 逐状态验证守恒律, 并用穷举得到的最短胜利距离证明BFS
+// 求解器的最优性. 状态空间穷举较重, -short下跳过.
+func TestExhaustiveTinyBoardProofs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("穷举证明在-short下跳过")
+	}
+	const stateLimit = 200000
+	for seed := int64(0); seed < 25; seed++ {
+		g := NewWaterBottleGameWithSeed(4, 3, 2, 2, 0, 0, false, seed)
+		want := colorCensus(g)
+		states, winDist := exhaustSearch(t, g, stateLimit, func() {
+			census := colorCensus(g)
+			for c, n := range want {
+				if census[c] != n {
+					t.Fatalf("种子%d: 某个可达状态里%s有%d格, 初始是%d格, 水不守恒",
+						seed, getColorName(c), census[c], n)
+				}
+			}
+		})
+		if winDist < 0 {
+			t.Fatalf("种子%d: 穷举了%d个状态也没有胜利状态, 生成的谜题不可解", seed, states)
+		}
+		path, ok := g.solveBFS(stateLimit)
+		if !ok {
+			t.Fatalf("种子%d: 穷举证明可解(%d步), 但BFS求解器没找到解", seed, winDist)
+		}
+		if len(path) != winDist {
+			t.Errorf("种子%d: BFS解%d步, 穷举最短距离%d步, 求解器不是最优", seed, len(path), winDist)
+		}
+	}
+}
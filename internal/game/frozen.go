@@ -0,0 +1,115 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 冰冻水格: 开局被冻住的水层, 在顶部时不能倒出, 解冻前整瓶的上层
+// 也搬不走它. 往冰冻层上倒同色水会把它融化(连带其下紧邻的同色冰).
+// 冰冻状态编码在水格值本身(颜色加frozenOffset), 和万能色一样随真实
+// 水格走快照/存档/规范键, 不需要额外的并行元数据.
+
+// frozenOffset 冰冻水格的编码偏移: 冰冻的颜色c存成c+frozenOffset,
+// 和正常颜色(1~MaxColors)与特殊负值都错开.
+const frozenOffset = 100
+
+// ErrFrozenLayer 冰冻水格不能倒出
+var ErrFrozenLayer = errors.New("水被冻住了, 先用同色水融化它")
+
+// isFrozenColor 水格值是否是冰冻编码(炸弹区间在bombOffset起, 要排除)
+func isFrozenColor(w int) bool {
+	return w >= frozenOffset && w < bombOffset
+}
+
+// thawColor 取冰冻水格的底色; 非冰冻值原样返回
+func thawColor(w int) int {
+	if isFrozenColor(w) {
+		return w - frozenOffset
+	}
+	return w
+}
+
+// freezeColor 把颜色编码成冰冻水格
+func freezeColor(c int) int {
+	return c + frozenOffset
+}
+
+// FreezeLayer 把容器bottle自底向上第layer层冻住, 用于脚本化关卡.
+// 万能格、炸弹格和已冰冻的格不能再冻: 炸弹编码在bombOffset区间,
+// 再加frozenOffset会越过colorByte的字节范围, 把快照和哈希弄脏.
+func (g *WaterBottleGame) FreezeLayer(bottle, layer int) error {
+	if bottle < 0 || bottle >= len(g.Bottles) {
+		return ErrInvalidIndex
+	}
+	b := g.Bottles[bottle]
+	if layer < 0 || layer >= len(b.Water) {
+		return fmt.Errorf("%w: %s只有%d层水", ErrInvalidIndex, g.ContainerName(bottle), len(b.Water))
+	}
+	w := b.Water[layer]
+	if w == WildcardColor || isFrozenColor(w) || isBombColor(w) {
+		return fmt.Errorf("这层水冻不了")
+	}
+	g.hash -= bottleHash(b)
+	b.Water[layer] = freezeColor(w)
+	g.hash += bottleHash(b)
+	return nil
+}
+
+// InjectFrozen 把场上n个随机水格冻住, 返回实际冻住的格数.
+// 和InjectWildcards一样由种子决定选格, 顶层不冻(开局就该有路可走).
+func (g *WaterBottleGame) InjectFrozen(n int) int {
+	type pos struct{ bottle, layer int }
+	var cells []pos
+	for i, b := range g.Bottles {
+		if b.Collected {
+			continue
+		}
+		for j, w := range b.Water {
+			if j == len(b.Water)-1 {
+				continue
+			}
+			if w != WildcardColor && !isFrozenColor(w) && !isBombColor(w) {
+				cells = append(cells, pos{i, j})
+			}
+		}
+	}
+	g.auditShuffle("冰冻:选格", len(cells), func(i, j int) {
+		cells[i], cells[j] = cells[j], cells[i]
+	})
+	if n > len(cells) {
+		n = len(cells)
+	}
+	for _, p := range cells[:n] {
+		b := g.Bottles[p.bottle]
+		g.hash -= bottleHash(b)
+		b.Water[p.layer] = freezeColor(b.Water[p.layer])
+		g.hash += bottleHash(b)
+	}
+	if n > 0 {
+		g.logger.Logf("🧊 冻住了%d格水", n)
+	}
+	return n
+}
+
+// meltAdjacent 一股颜色c的水落到容器第bottom层之上后, 向下融化紧邻的
+// 同色冰冻格(连续的同色冰一起化). pourOne在搬水后调用.
+func (g *WaterBottleGame) meltAdjacent(bottle, bottom, c int) {
+	if c == WildcardColor {
+		return
+	}
+	b := g.Bottles[bottle]
+	melted := 0
+	g.hash -= bottleHash(b)
+	for j := bottom - 1; j >= 0; j-- {
+		if !isFrozenColor(b.Water[j]) || thawColor(b.Water[j]) != c {
+			break
+		}
+		b.Water[j] = thawColor(b.Water[j])
+		melted++
+	}
+	g.hash += bottleHash(b)
+	if melted > 0 && !g.suppressEvents {
+		g.logger.Logf("💧 %s里的%d格%s冰融化了", g.ContainerName(bottle), melted, getColorName(c))
+	}
+}
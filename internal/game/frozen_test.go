@@ -0,0 +1,24 @@
+package game
+
+import "testing"
+
+// TestFreezeLayerRejectsSpecialCells 万能格/炸弹格/已冰冻格都不能再冻:
+// 炸弹再叠frozenOffset会超出colorByte的编码范围, 污染快照和哈希
+func TestFreezeLayerRejectsSpecialCells(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(5, 4, 3, 2, 0, 0, false, 3)
+	b := g.Bottles[0]
+	b.Water[0] = armBomb(b.Water[0])
+	if err := g.FreezeLayer(0, 0); err == nil {
+		t.Errorf("冻炸弹格应该报错")
+	}
+	b.Water[1] = WildcardColor
+	if err := g.FreezeLayer(0, 1); err == nil {
+		t.Errorf("冻万能格应该报错")
+	}
+	if err := g.FreezeLayer(0, 2); err != nil {
+		t.Fatalf("冻普通格应该成功: %v", err)
+	}
+	if err := g.FreezeLayer(0, 2); err == nil {
+		t.Errorf("重复冻同一格应该报错")
+	}
+}
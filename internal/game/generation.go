@@ -0,0 +1,61 @@
+package game
+
+import "time"
+
+// 生成报告: 以前生成器把尝试次数打在日志里就扔掉了, 批量生成和
+// 服务器模式都想要这些数字做统计. 每次生成结束后把本轮的情况记成
+// GenerationReport, 由LastGenerationReport读取; 重试上限也从写死的
+// 常量改成可配置.
+
+// defaultGenerationRetries 生成验证失败时的默认重试上限
+const defaultGenerationRetries = 10
+
+// GenerationReport 一轮谜题生成的统计
+type GenerationReport struct {
+	RequestedSteps int           `json:"requestedSteps"` // 请求的打乱步数, 随机生成时为0
+	ActualSteps    int           `json:"actualSteps"`    // 实际做到的打乱步数
+	Attempts       int           `json:"attempts"`       // 用掉的尝试次数
+	SuccessRate    float64       `json:"successRate"`    // 成功次数/尝试次数, 失败时为0
+	Elapsed        time.Duration `json:"elapsed"`        // 总耗时
+}
+
+// Succeeded 本轮生成是否得到了通过验证的谜题
+func (r GenerationReport) Succeeded() bool {
+	return r.SuccessRate > 0
+}
+
+// SetGenerationRetries 配置生成的重试上限, 0或负数恢复默认值
+func (g *WaterBottleGame) SetGenerationRetries(n int) {
+	if n <= 0 {
+		n = 0
+	}
+	g.genRetries = n
+}
+
+// generationRetries 当前生效的重试上限
+func (g *WaterBottleGame) generationRetries() int {
+	if g.genRetries > 0 {
+		return g.genRetries
+	}
+	return defaultGenerationRetries
+}
+
+// LastGenerationReport 最近一轮生成的统计报告
+func (g *WaterBottleGame) LastGenerationReport() GenerationReport {
+	return g.genReport
+}
+
+// recordGeneration 记录一轮生成的结果. attempts是用掉的尝试次数,
+// ok表示最终是否成功; 成功率按"一次成功其余失败"折算.
+func (g *WaterBottleGame) recordGeneration(requested, actual, attempts int, ok bool, start time.Time) {
+	r := GenerationReport{
+		RequestedSteps: requested,
+		ActualSteps:    actual,
+		Attempts:       attempts,
+		Elapsed:        time.Since(start),
+	}
+	if ok && attempts > 0 {
+		r.SuccessRate = 1 / float64(attempts)
+	}
+	g.genReport = r
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 消息目录: 用户可见的文案按ID放进语言包, 通过SetLanguage整体切换.
+// 目前收录颜色名和倒水失败原因这两类最常被上层展示的文案;
+// 演示程序自己的提示语仍然是中文, 不在目录范围内.
+
+// langBundle 一种语言的文案包
+type langBundle struct {
+	colorNames []string          // 颜色名, 下标与颜色编号对应
+	msgs       map[string]string // 其余文案, 按ID索引
+}
+
+var bundles = map[string]*langBundle{
+	"zh-CN": {
+		colorNames: colorNames,
+		msgs: map[string]string{
+			"color.wild":       "万能",
+			"color.unknown":    "颜色%d",
+			"err.invalidIndex": "瓶子编号超出范围",
+			"err.sameBottle":   "不能倒给自己",
+			"err.collected":    "瓶子已被袋子收集",
+			"err.sourceEmpty":  "源瓶是空的",
+			"err.targetFull":   "目标瓶已满",
+			"err.mismatch":     "颜色不匹配: %s不能倒在%s上",
+			"err.strictLocked": "严格模式下不能拆开已完成的瓶子",
+		},
+	},
+	"en-US": {
+		colorNames: []string{"", "red", "blue", "green", "yellow", "purple",
+			"orange", "cyan", "pink", "brown", "gray"},
+		msgs: map[string]string{
+			"color.wild":       "wildcard",
+			"color.unknown":    "color %d",
+			"err.invalidIndex": "bottle index out of range",
+			"err.sameBottle":   "cannot pour into the same bottle",
+			"err.collected":    "bottle already collected by a bag",
+			"err.sourceEmpty":  "source bottle is empty",
+			"err.targetFull":   "target bottle is full",
+			"err.mismatch":     "color mismatch: cannot pour %s onto %s",
+			"err.strictLocked": "strict mode: cannot break up a completed bottle",
+		},
+	},
+}
+
+// currentLang 当前语言, 默认中文
+var currentLang = "zh-CN"
+
+// SetLanguage 切换文案语言, 支持zh-CN和en-US
+func SetLanguage(tag string) error {
+	if _, ok := bundles[tag]; !ok {
+		return fmt.Errorf("不支持的语言: %s", tag)
+	}
+	currentLang = tag
+	return nil
+}
+
+// tr 按当前语言取一条文案, 语言包里缺这条时退回中文
+func tr(id string) string {
+	if s, ok := bundles[currentLang].msgs[id]; ok {
+		return s
+	}
+	return bundles["zh-CN"].msgs[id]
+}
+
+// errMsgIDs 类型化倒水错误对应的文案ID
+var errMsgIDs = map[error]string{
+	ErrInvalidIndex:     "err.invalidIndex",
+	ErrSameBottle:       "err.sameBottle",
+	ErrCollectedBottle:  "err.collected",
+	ErrSourceEmpty:      "err.sourceEmpty",
+	ErrTargetFull:       "err.targetFull",
+	ErrStrictModeLocked: "err.strictLocked",
+}
+
+// localizePourError 把validatePour的错误翻译成当前语言的文案
+func (g *WaterBottleGame) localizePourError(from, to int, err error) string {
+	if errors.Is(err, ErrColorMismatch) {
+		return fmt.Sprintf(tr("err.mismatch"),
+			getColorName(g.Bottles[from].TopColor()), getColorName(g.Bottles[to].TopColor()))
+	}
+	if id, ok := errMsgIDs[err]; ok {
+		return tr(id)
+	}
+	return err.Error()
+}
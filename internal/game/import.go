@@ -0,0 +1,159 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// 外部谜题导入: 各家倒水游戏的关卡在社区里有几种常见的交换格式,
+// 这里都认, 玩家可以把别的app里的谜题搬进引擎来求解/分析:
+//   - 字母式: 每个容器一段字母(瓶底在前), 逗号或换行分隔,
+//     空瓶写"-"或留空, 例如 "RGBY,YBGR,RYBG,GYRB,-,-"
+//   - tubes JSON: 截图识别工具常用的 {"tubes":[["red","blue"],[]]},
+//     颜色名是什么词不重要, 同名算同色
+//   - 数字矩阵: [[1,2,3,4],[4,3,2,1],[]], 和GetState同口径
+// 颜色一律按首次出现的顺序重新编号成1..N.
+
+// ImportPuzzle 解析一段外部格式的谜题文本并开成一局可玩的游戏.
+// 先按内容猜格式, 三种都解不出来时报错.
+func ImportPuzzle(text string) (*WaterBottleGame, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("导入内容为空")
+	}
+	var tubes [][]string
+	var err error
+	switch {
+	case strings.HasPrefix(text, "{"):
+		tubes, err = parseTubesJSON(text)
+	case strings.HasPrefix(text, "["):
+		tubes, err = parseStateJSON(text)
+	default:
+		tubes, err = parseLetterTubes(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return gameFromTubes(tubes)
+}
+
+// parseTubesJSON 解析截图识别工具的 {"tubes":[[...],[...]]} 格式
+func parseTubesJSON(text string) ([][]string, error) {
+	var doc struct {
+		Tubes [][]string `json:"tubes"`
+	}
+	if err := json.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, fmt.Errorf("tubes JSON解析失败: %w", err)
+	}
+	if len(doc.Tubes) == 0 {
+		return nil, fmt.Errorf("tubes JSON里没有任何容器")
+	}
+	return doc.Tubes, nil
+}
+
+// parseStateJSON 解析 [[1,2],[2,1],[]] 形式的数字矩阵
+func parseStateJSON(text string) ([][]string, error) {
+	var state [][]int
+	if err := json.Unmarshal([]byte(text), &state); err != nil {
+		return nil, fmt.Errorf("数字矩阵解析失败: %w", err)
+	}
+	if len(state) == 0 {
+		return nil, fmt.Errorf("数字矩阵里没有任何容器")
+	}
+	tubes := make([][]string, len(state))
+	for i, tube := range state {
+		for _, c := range tube {
+			tubes[i] = append(tubes[i], fmt.Sprintf("%d", c))
+		}
+	}
+	return tubes, nil
+}
+
+// parseLetterTubes 解析字母式编码, 逗号/换行分隔, 每个字母一层
+func parseLetterTubes(text string) ([][]string, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ';'
+	})
+	var tubes [][]string
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" || f == "-" {
+			tubes = append(tubes, nil)
+			continue
+		}
+		var tube []string
+		for _, r := range f {
+			tube = append(tube, strings.ToUpper(string(r)))
+		}
+		tubes = append(tubes, tube)
+	}
+	if len(tubes) == 0 {
+		return nil, fmt.Errorf("字母式编码里没有任何容器")
+	}
+	return tubes, nil
+}
+
+// gameFromTubes 把统一成字符串层的容器列表开成一局游戏: 颜色按
+// 首次出现顺序编号, 容量取最满容器的层数, 再做守恒检查 —
+// 每种颜色的总层数必须正好等于容量, 不然这盘理论上就解不完.
+func gameFromTubes(tubes [][]string) (*WaterBottleGame, error) {
+	capacity := 0
+	for _, tube := range tubes {
+		if len(tube) > capacity {
+			capacity = len(tube)
+		}
+	}
+	if capacity == 0 {
+		return nil, fmt.Errorf("所有容器都是空的, 没有谜题可导入")
+	}
+	if capacity > MaxCapacity {
+		// 压缩快照的定长数组装不下更深的容器, 放进来第一步就会崩
+		return nil, fmt.Errorf("容器最深%d层, 超过引擎支持的上限%d", capacity, MaxCapacity)
+	}
+	colorID := make(map[string]int)
+	counts := make(map[int]int)
+	empties := 0
+	bottles := make([][]int, len(tubes))
+	for i, tube := range tubes {
+		if len(tube) == 0 {
+			empties++
+			continue
+		}
+		for _, name := range tube {
+			c, ok := colorID[name]
+			if !ok {
+				c = len(colorID) + 1
+				colorID[name] = c
+			}
+			counts[c]++
+			bottles[i] = append(bottles[i], c)
+		}
+	}
+	for name, c := range colorID {
+		if counts[c] != capacity {
+			return nil, fmt.Errorf("颜色%q有%d层, 凑不满一瓶(容量%d), 谜题无法成立",
+				name, counts[c], capacity)
+		}
+	}
+	seed := time.Now().UnixNano()
+	g := &WaterBottleGame{
+		BottleCount:     len(tubes),
+		Capacity:        capacity,
+		ColorCount:      len(colorID),
+		EmptyBottles:    empties,
+		collectedColors: make(map[int]bool),
+		rng:             rand.New(rand.NewSource(seed)),
+		seed:            seed,
+		audit:           RandAuditLog{Seed: seed},
+		logger:          SilentLogger,
+		startedAt:       time.Now(),
+		scoring:         DefaultScoringConfig(),
+	}
+	for _, water := range bottles {
+		g.Bottles = append(g.Bottles, &Bottle{Water: water, Capacity: capacity})
+	}
+	return g, nil
+}
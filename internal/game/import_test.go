@@ -0,0 +1,52 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestImportPuzzleFormats 三种外部格式都应解析出同一个局面:
+// 6个容器, 4色, 2空, 颜色按首次出现顺序编号.
+func TestImportPuzzleFormats(t *testing.T) {
+	inputs := map[string]string{
+		"字母式":        "RGBY,YBGR,RYBG,GYRB,-,-",
+		"tubes JSON": `{"tubes":[["red","green","blue","yellow"],["yellow","blue","green","red"],["red","yellow","blue","green"],["green","yellow","red","blue"],[],[]]}`,
+		"数字矩阵":       `[[1,2,3,4],[4,3,2,1],[1,4,3,2],[2,4,1,3],[],[]]`,
+	}
+	for name, text := range inputs {
+		g, err := ImportPuzzle(text)
+		if err != nil {
+			t.Fatalf("%s导入失败: %v", name, err)
+		}
+		if len(g.Bottles) != 6 || g.ColorCount != 4 || g.Capacity != 4 || g.EmptyBottles != 2 {
+			t.Errorf("%s导入的参数不对: %d瓶 %d色 容量%d 空瓶%d",
+				name, len(g.Bottles), g.ColorCount, g.Capacity, g.EmptyBottles)
+		}
+		if got := g.Bottles[0].Water; len(got) != 4 || got[0] != 1 || got[3] != 4 {
+			t.Errorf("%s导入的首瓶内容不对: %v", name, got)
+		}
+		// 导入的局面必须能直接开玩, 倒一步不应崩
+		if _, err := g.Pour(0, 4); err != nil {
+			t.Errorf("%s导入后第一步倒水失败: %v", name, err)
+		}
+	}
+}
+
+// TestImportPuzzleRejectsBadInput 不合法的外部输入要报错而不是开出坏局
+func TestImportPuzzleRejectsBadInput(t *testing.T) {
+	cases := map[string]string{
+		"空内容":    "   ",
+		"全空容器":   "-,-,-",
+		"颜色凑不满":  "RGB,GRB,BG,-", // 容量3, 但R只有2层
+		"坏JSON":  `{"tubes":[["red"]`,
+		"空tubes": `{"tubes":[]}`,
+		"坏数字矩阵":  `[[1,2],"x"]`,
+		"超深容器":   strings.Repeat("R", MaxCapacity+1) + "," + strings.Repeat("G", MaxCapacity+1) + ",-",
+		"颜色层数不对": "RRG,GGR,RG,-",
+	}
+	for name, text := range cases {
+		if _, err := ImportPuzzle(text); err == nil {
+			t.Errorf("%s应该报错, 却导入成功", name)
+		}
+	}
+}
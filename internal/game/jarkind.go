@@ -0,0 +1,54 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 罐子行为变体: 罐子不再只是更大的瓶子, 可以按关卡设计配成三种
+// 特殊行为. 变体只挂在罐子上(普通瓶子忽略Kind), 倒水校验和胜负
+// 判定各自检查对应的规则, 求解器通过validatePour自动遵守.
+
+// JarKind 罐子的行为变体
+type JarKind int
+
+const (
+	JarNormal      JarKind = iota // 普通罐子: 只是更大的瓶子
+	JarBuffer                     // 缓冲罐: 无视顶层颜色, 任何水都能往里倒
+	JarReceiveOnly                // 只进罐: 只能往里倒, 不能往外倒
+	JarSealed                     // 封印罐: 必须倒空才能判胜
+)
+
+// String 返回变体的中文名称
+func (k JarKind) String() string {
+	switch k {
+	case JarNormal:
+		return "普通罐"
+	case JarBuffer:
+		return "缓冲罐"
+	case JarReceiveOnly:
+		return "只进罐"
+	case JarSealed:
+		return "封印罐"
+	}
+	return fmt.Sprintf("未知变体(%d)", int(k))
+}
+
+// ErrJarReceiveOnly 只进罐不能往外倒
+var ErrJarReceiveOnly = errors.New("只进罐不能往外倒")
+
+// SetJarKind 给第index个容器配置罐子变体, 只有罐子能配置.
+func (g *WaterBottleGame) SetJarKind(index int, kind JarKind) error {
+	if index < 0 || index >= len(g.Bottles) {
+		return ErrInvalidIndex
+	}
+	b := g.Bottles[index]
+	if !b.IsJar {
+		return fmt.Errorf("%s不是罐子, 配不了罐子变体", g.ContainerName(index))
+	}
+	if kind < JarNormal || kind > JarSealed {
+		return fmt.Errorf("未知的罐子变体: %d", int(kind))
+	}
+	b.Kind = kind
+	return nil
+}
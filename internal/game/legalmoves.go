@@ -0,0 +1,64 @@
+package game
+
+// 合法走子的枚举入口. 机器人, 死局检测和UI高亮都要反复枚举走子,
+// 这里提供一个零分配的回调版本和一个方便调用的切片版本,
+// 文案型的CheckPossibleMoves也基于它实现.
+
+// ForEachLegalMove 按(from, to)字典序枚举当前所有合法走子(带可倒
+// 层数), 逐个回调fn; fn返回false时提前终止. 自身不分配也不改动
+// 任何状态, 适合每帧高频调用.
+func (g *WaterBottleGame) ForEachLegalMove(fn func(Move) bool) {
+	for from := 0; from < len(g.Bottles); from++ {
+		for to := 0; to < len(g.Bottles); to++ {
+			if from == to {
+				continue
+			}
+			if amount, err := g.validatePour(from, to); err == nil {
+				if !fn(Move{From: from, To: to, Amount: amount}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// LegalMoves 返回当前所有合法走子
+func (g *WaterBottleGame) LegalMoves() []Move {
+	var out []Move
+	g.ForEachLegalMove(func(m Move) bool {
+		out = append(out, m)
+		return true
+	})
+	return out
+}
+
+// WouldDeadlock 预判一次倒水是否会把局面带进死局: 模拟这步倒水,
+// 看结果局面是否既没有获胜也没有任何合法走子. UI可以在玩家落子前
+// 提示"这步是条死路". 这步本身不合法时返回false.
+func (g *WaterBottleGame) WouldDeadlock(from, to int) bool {
+	if _, err := g.validatePour(from, to); err != nil {
+		return false
+	}
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	snap := g.borrowSnapshot()
+	defer func() {
+		g.restoreGameState(snap)
+		releaseSnapshot(snap)
+	}()
+	if _, err := g.Pour(from, to); err != nil {
+		return false
+	}
+	return !g.IsWon() && !g.hasLegalMove()
+}
+
+// hasLegalMove 是否还有任何合法走子, 找到第一个就停
+func (g *WaterBottleGame) hasLegalMove() bool {
+	found := false
+	g.ForEachLegalMove(func(Move) bool {
+		found = true
+		return false
+	})
+	return found
+}
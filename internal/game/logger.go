@@ -0,0 +1,35 @@
+package game
+
+import "fmt"
+
+// Logger 接收引擎运行过程中的提示信息(生成进度/收集通知等).
+// 默认使用静默实现, 引擎可以在服务器/求解器/测试里无输出地运行.
+type Logger interface {
+	Logf(format string, args ...interface{})
+}
+
+// silentLogger 丢弃所有消息
+type silentLogger struct{}
+
+func (silentLogger) Logf(string, ...interface{}) {}
+
+// stdoutLogger 把消息打印到标准输出
+type stdoutLogger struct{}
+
+func (stdoutLogger) Logf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
+
+// SilentLogger 静默输出, 引擎的默认值
+var SilentLogger Logger = silentLogger{}
+
+// StdoutLogger 打印到标准输出, 供命令行演示使用
+var StdoutLogger Logger = stdoutLogger{}
+
+// SetLogger 设置引擎消息的输出目标, 传nil恢复静默
+func (g *WaterBottleGame) SetLogger(l Logger) {
+	if l == nil {
+		l = SilentLogger
+	}
+	g.logger = l
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MoveLogEntry 一个真实回合的记录. 双手模式的回合带上第二股倒水,
+// 重放时两股水在同一回合里原子生效.
+type MoveLogEntry struct {
+	Move   Move      `json:"move"`
+	Second *Move     `json:"second,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// MoveLog 一局游戏的走子日志, 连同种子一起导出后可以在别处重放
+type MoveLog struct {
+	Seed    int64          `json:"seed"`
+	Entries []MoveLogEntry `json:"entries"`
+}
+
+// MoveLog 返回当前对局的走子日志
+func (g *WaterBottleGame) MoveLog() MoveLog {
+	return MoveLog{Seed: g.seed, Entries: append([]MoveLogEntry(nil), g.moveLog...)}
+}
+
+// ExportMoveLog 把走子日志导出为JSON
+func (g *WaterBottleGame) ExportMoveLog() ([]byte, error) {
+	return json.MarshalIndent(g.MoveLog(), "", "  ")
+}
+
+// ExportMoveLogCompact 导出紧凑文本记谱, 形如 "0>3x2 1>4x1"
+func (g *WaterBottleGame) ExportMoveLogCompact() string {
+	var parts []string
+	for _, e := range g.moveLog {
+		part := fmt.Sprintf("%d>%dx%d", e.Move.From, e.Move.To, e.Move.Amount)
+		if e.Second != nil {
+			// 双手回合用+连接两股水
+			part += fmt.Sprintf("+%d>%dx%d", e.Second.From, e.Second.To, e.Second.Amount)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseMoveLog 解析ExportMoveLog导出的JSON
+func ParseMoveLog(data []byte) (MoveLog, error) {
+	var log MoveLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return MoveLog{}, fmt.Errorf("走子日志解析失败: %w", err)
+	}
+	return log, nil
+}
+
+// Replay 按当前对局的参数和日志中的种子重建初始局面, 并重放全部走子,
+// 用于验证和回看一局完整的游戏. 返回重放结束后的对局.
+func (g *WaterBottleGame) Replay(log MoveLog) (*WaterBottleGame, error) {
+	ng := NewWaterBottleGameWithSeed(g.BottleCount, g.Capacity, g.ColorCount,
+		g.EmptyBottles, g.JarCount, g.JarCapacity, g.UseBags, log.Seed)
+	ng.StrictMode = g.StrictMode
+	ng.Rules = g.Rules
+	for i, e := range log.Entries {
+		var err error
+		if e.Second != nil {
+			_, _, err = ng.PourBoth(e.Move.From, e.Move.To, e.Second.From, e.Second.To)
+		} else if e.Move.Amount > 0 {
+			// 按记录的层数重放, 部分倒水(PourAmount)也能还原
+			_, err = ng.PourAmount(e.Move.From, e.Move.To, e.Move.Amount)
+		} else {
+			_, err = ng.Pour(e.Move.From, e.Move.To)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("重放失败: 第%d步 %d->%d 不合法: %w", i+1, e.Move.From, e.Move.To, err)
+		}
+	}
+	return ng, nil
+}
@@ -0,0 +1,81 @@
+package game
+
+// 隐藏层模式: 开局只能看到每个容器的顶层, 下面的层在轮到顶部时才被
+// 揭示. 揭示是单调的 —— 见过的层即使再被盖住也保持可见(玩家记得住),
+// 撤销也不会把知识收回去. 求解器和提示依旧看全量局面, 属于"作弊"
+// 范畴, 由调用方决定要不要在该模式下提供.
+
+// SetMysteryMode 开关隐藏层模式. 开启时重置揭示状态:
+// 只有每个容器的当前顶层可见.
+func (g *WaterBottleGame) SetMysteryMode(on bool) {
+	g.Mystery = on
+	g.revealed = nil
+	if on {
+		g.revealCurrentTops()
+	}
+}
+
+// ensureRevealed 保证第i个容器的揭示标记数组存在且够长
+func (g *WaterBottleGame) ensureRevealed(i int) {
+	for len(g.revealed) <= i {
+		g.revealed = append(g.revealed, nil)
+	}
+	for len(g.revealed[i]) < g.Bottles[i].Capacity {
+		g.revealed[i] = append(g.revealed[i], false)
+	}
+}
+
+// revealCurrentTops 把每个容器当前的顶层标记为已见.
+// 模拟(求解/生成)中的走子不揭示任何层, 知识只来自玩家真实看到的画面.
+func (g *WaterBottleGame) revealCurrentTops() {
+	if !g.Mystery || g.suppressEvents {
+		return
+	}
+	for i, b := range g.Bottles {
+		g.ensureRevealed(i)
+		if n := len(b.Water); n > 0 {
+			g.revealed[i][n-1] = true
+		}
+	}
+}
+
+// revealRange 把容器i的第from到to-1层(自底向上)标记为已见,
+// 倒进来的水玩家当然看得到.
+func (g *WaterBottleGame) revealRange(i, from, to int) {
+	if !g.Mystery || g.suppressEvents {
+		return
+	}
+	g.ensureRevealed(i)
+	for j := from; j < to && j < len(g.revealed[i]); j++ {
+		g.revealed[i][j] = true
+	}
+}
+
+// isRevealed 容器i的第j层是否已被揭示; 非隐藏层模式下恒为真
+func (g *WaterBottleGame) isRevealed(i, j int) bool {
+	if !g.Mystery {
+		return true
+	}
+	if i >= len(g.revealed) || j >= len(g.revealed[i]) {
+		return false
+	}
+	return g.revealed[i][j]
+}
+
+// VisibleState 玩家视角的局面: 和GetState同构, 但未揭示的层是
+// HiddenColor. UI渲染和机器人都应该用它, GetState留给存档和调试.
+func (g *WaterBottleGame) VisibleState() [][]int {
+	var out [][]int
+	for i, b := range g.Bottles {
+		var layers []int
+		for j, w := range b.Water {
+			if g.isRevealed(i, j) {
+				layers = append(layers, w)
+			} else {
+				layers = append(layers, HiddenColor)
+			}
+		}
+		out = append(out, layers)
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// 走子记谱: 一步倒水写成"B0>B3x2"(瓶子0往瓶子3倒2层), 罐子用J前缀
+// 按罐子序号计("B5>J1x1"是倒进第1号罐子). 日志/回放/脚本/CLI共用
+// 这一种文本表示. B后面是容器的绝对下标, J的序号要靠对局里的
+// BottleCount换算, 所以解析分两层: 包级ParseMove只认B形式,
+// 对局方法ParseNotation两种都认.
+
+// moveNotation 记谱的正则: 前缀+下标, 可选的x层数
+var moveNotation = regexp.MustCompile(`^([BJbj])(\d+)>([BJbj])(\d+)(?:[xX](\d+))?$`)
+
+// String 把走子写成记谱形式. 不知道对局的瓶罐分界, 容器一律用
+// 绝对下标的B形式; 要得到J形式用Game.FormatMove.
+func (m Move) String() string {
+	return fmt.Sprintf("B%d>B%dx%d", m.From, m.To, m.Amount)
+}
+
+// ParseMove 解析一条B形式的记谱. 层数省略时Amount为0,
+// 表示"倒整段"(Pour的默认行为). J形式需要对局上下文,
+// 这里直接报错, 请用Game.ParseNotation.
+func ParseMove(s string) (Move, error) {
+	parts := moveNotation.FindStringSubmatch(s)
+	if parts == nil {
+		return Move{}, fmt.Errorf("记谱格式不对: %q (应形如 B0>B3x2)", s)
+	}
+	if parts[1] == "J" || parts[1] == "j" || parts[3] == "J" || parts[3] == "j" {
+		return Move{}, fmt.Errorf("罐子记谱%q需要对局上下文, 用Game.ParseNotation解析", s)
+	}
+	return parseMoveParts(parts, 0)
+}
+
+// ParseNotation 在本局上下文里解析记谱, J序号换算成绝对容器下标
+func (g *WaterBottleGame) ParseNotation(s string) (Move, error) {
+	parts := moveNotation.FindStringSubmatch(s)
+	if parts == nil {
+		return Move{}, fmt.Errorf("记谱格式不对: %q (应形如 B0>B3x2 或 B5>J1x1)", s)
+	}
+	return parseMoveParts(parts, g.BottleCount)
+}
+
+// parseMoveParts 把正则捕获组装成Move, J前缀的下标加上罐子基准
+func parseMoveParts(parts []string, jarBase int) (Move, error) {
+	idx := func(prefix, num string) int {
+		n, _ := strconv.Atoi(num)
+		if prefix == "J" || prefix == "j" {
+			n += jarBase
+		}
+		return n
+	}
+	m := Move{From: idx(parts[1], parts[2]), To: idx(parts[3], parts[4])}
+	if parts[5] != "" {
+		m.Amount, _ = strconv.Atoi(parts[5])
+	}
+	return m, nil
+}
+
+// FormatMove 把走子写成本局的记谱形式, 罐子用J序号
+func (g *WaterBottleGame) FormatMove(m Move) string {
+	part := func(i int) string {
+		if i >= g.BottleCount && i < len(g.Bottles) {
+			return fmt.Sprintf("J%d", i-g.BottleCount)
+		}
+		return fmt.Sprintf("B%d", i)
+	}
+	s := fmt.Sprintf("%s>%s", part(m.From), part(m.To))
+	if m.Amount > 0 {
+		s += fmt.Sprintf("x%d", m.Amount)
+	}
+	return s
+}
+
+// PlayNotation 解析并执行一条记谱. 层数省略或为0时倒整段,
+// 否则按指定层数走PourAmount.
+func (g *WaterBottleGame) PlayNotation(s string) (int, error) {
+	m, err := g.ParseNotation(s)
+	if err != nil {
+		return 0, err
+	}
+	if m.Amount > 0 {
+		return g.PourAmount(m.From, m.To, m.Amount)
+	}
+	return g.Pour(m.From, m.To)
+}
@@ -0,0 +1,70 @@
+package game
+
+import "testing"
+
+// TestNotationRoundTrip 记谱解析与格式化互为逆运算, J序号换算正确
+func TestNotationRoundTrip(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 2, 6, false, 9)
+	cases := map[string]Move{
+		"B0>B3":    {From: 0, To: 3},
+		"B0>B3x2":  {From: 0, To: 3, Amount: 2},
+		"b1>b2X1":  {From: 1, To: 2, Amount: 1},
+		"B5>J0":    {From: 5, To: 6},
+		"J1>B0x3":  {From: 7, To: 0, Amount: 3},
+		"B0>B10x1": {From: 0, To: 10, Amount: 1},
+	}
+	for s, want := range cases {
+		m, err := g.ParseNotation(s)
+		if err != nil {
+			t.Errorf("解析%q失败: %v", s, err)
+			continue
+		}
+		if m != want {
+			t.Errorf("解析%q得到%+v, 期望%+v", s, m, want)
+		}
+	}
+	// 罐子走子格式化回J形式
+	if s := g.FormatMove(Move{From: 6, To: 0, Amount: 2}); s != "J0>B0x2" {
+		t.Errorf("罐子走子应写成J0>B0x2, 得到%q", s)
+	}
+	if s := g.FormatMove(Move{From: 0, To: 3}); s != "B0>B3" {
+		t.Errorf("省略层数的走子应写成B0>B3, 得到%q", s)
+	}
+}
+
+// TestNotationRejectsBadInput 不合法的记谱一律报错
+func TestNotationRejectsBadInput(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 0, 0, false, 9)
+	for _, s := range []string{"", "B0", "B0>B", "0>3", "B0-B3", "B0>B3x", "B0>B3x2x3", "B0 > B3"} {
+		if _, err := g.ParseNotation(s); err == nil {
+			t.Errorf("记谱%q应该报错", s)
+		}
+	}
+	// 包级ParseMove不认J形式, 它没有瓶罐分界信息
+	if _, err := ParseMove("J0>B1"); err == nil {
+		t.Errorf("ParseMove对J形式应该报错")
+	}
+	if _, err := ParseMove("B0>B1x2"); err != nil {
+		t.Errorf("ParseMove对B形式应该成功: %v", err)
+	}
+}
+
+// TestPlayNotation 执行记谱走子: 省略层数倒整段, 带层数按层数倒
+func TestPlayNotation(t *testing.T) {
+	g, err := NewWaterBottleGameFromState(3, 4, 2, 1, 0, 0, false, 1,
+		[][]int{{2, 1, 1}, {1, 2, 2}, {}})
+	if err != nil {
+		t.Fatalf("构造局面失败: %v", err)
+	}
+	n, err := g.PlayNotation("B0>B2")
+	if err != nil || n != 2 {
+		t.Fatalf("B0>B2应倒整段2层, 得到%d, err=%v", n, err)
+	}
+	n, err = g.PlayNotation("B1>B0x1")
+	if err != nil || n != 1 {
+		t.Fatalf("B1>B0x1应倒1层, 得到%d, err=%v", n, err)
+	}
+	if _, err := g.PlayNotation("B0>B9"); err == nil {
+		t.Errorf("倒向不存在的瓶子应该报错")
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import "fmt"
+
+// 关卡目标系统: 在胜负之外给一局附加可选目标("先完成红色"/"20步内
+// 通关"/"全程不用罐子"), 由走子日志和引擎过程记录判定, 随时可以查
+// 清单. 目标不影响对局进程, 完成与否只体现在结算里.
+
+// Objective 一条关卡目标
+type Objective interface {
+	Describe() string              // 目标的中文描述
+	Check(g *WaterBottleGame) bool // 当前是否达成
+}
+
+// ObjectiveResult 清单里的一项: 描述加达成状态
+type ObjectiveResult struct {
+	Description string `json:"description"`
+	Done        bool   `json:"done"`
+}
+
+// objectiveFunc 函数式的Objective实现, 内置目标都用它
+type objectiveFunc struct {
+	desc  string
+	check func(g *WaterBottleGame) bool
+}
+
+func (o objectiveFunc) Describe() string              { return o.desc }
+func (o objectiveFunc) Check(g *WaterBottleGame) bool { return o.check(g) }
+
+// SetObjectives 给本局设置目标清单, 覆盖之前的设置
+func (g *WaterBottleGame) SetObjectives(objs ...Objective) {
+	g.objectives = objs
+}
+
+// Objectives 本局的目标清单
+func (g *WaterBottleGame) Objectives() []Objective {
+	return g.objectives
+}
+
+// CheckObjectives 按当前局面判定整个清单
+func (g *WaterBottleGame) CheckObjectives() []ObjectiveResult {
+	var out []ObjectiveResult
+	for _, o := range g.objectives {
+		out = append(out, ObjectiveResult{Description: o.Describe(), Done: o.Check(g)})
+	}
+	return out
+}
+
+// ObjectiveCompleteColorFirst 第一个完成的瓶子必须是指定颜色
+func ObjectiveCompleteColorFirst(color int) Objective {
+	return objectiveFunc{
+		desc: fmt.Sprintf("先完成%s的瓶子", getColorName(color)),
+		check: func(g *WaterBottleGame) bool {
+			return len(g.completionOrder) > 0 && g.completionOrder[0] == color
+		},
+	}
+}
+
+// ObjectiveMaxMoves 在指定步数内通关
+func ObjectiveMaxMoves(n int) Objective {
+	return objectiveFunc{
+		desc: fmt.Sprintf("%d步内通关", n),
+		check: func(g *WaterBottleGame) bool {
+			return g.IsWon() && g.MoveCount <= n
+		},
+	}
+}
+
+// ObjectiveNoJar 全程不碰罐子(倒入倒出都算碰)
+func ObjectiveNoJar() Objective {
+	return objectiveFunc{
+		desc: "全程不使用罐子",
+		check: func(g *WaterBottleGame) bool {
+			touches := func(m Move) bool {
+				return m.From >= g.BottleCount || m.To >= g.BottleCount
+			}
+			for _, e := range g.moveLog {
+				if touches(e.Move) || (e.Second != nil && touches(*e.Second)) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// ObjectiveNoUndo 全程不使用撤销
+func ObjectiveNoUndo() Objective {
+	return objectiveFunc{
+		desc: "全程不使用撤销",
+		check: func(g *WaterBottleGame) bool {
+			return g.stats.Undos == 0
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+// VisibilityRule 观测的可见性规则
+type VisibilityRule int
+
+const (
+	VisibilityFull    VisibilityRule = iota // 全部水层可见
+	VisibilityTopOnly                       // 只能看到每个容器最上面一层
+	VisibilityTopTwo                        // 只能看到每个容器最上面两层
+)
+
+// HiddenColor 观测中表示"看不到"的水层
+const HiddenColor = -1
+
+// Observation 某种可见性规则下的观测结果.
+// 同一个底层状态可以按不同规则生成观测, 强化学习环境研究部分可见
+// 变体时不需要复制引擎逻辑; 引擎真实状态不受影响.
+type Observation struct {
+	Rule      VisibilityRule
+	Bottles   [][]int // 每个容器自底向上的颜色, 不可见层为HiddenColor
+	Collected []bool  // 容器是否已被袋子收集
+	BagColors []int   // 各袋子当前需要的颜色
+}
+
+// visibleLayers 某个规则下从瓶口往下可见的层数, -1表示全部可见
+func (r VisibilityRule) visibleLayers() int {
+	switch r {
+	case VisibilityTopOnly:
+		return 1
+	case VisibilityTopTwo:
+		return 2
+	}
+	return -1
+}
+
+// Observe 按可见性规则对当前状态做掩码, 生成观测
+func (g *WaterBottleGame) Observe(rule VisibilityRule) Observation {
+	obs := Observation{Rule: rule}
+	visible := rule.visibleLayers()
+	for _, b := range g.Bottles {
+		layers := make([]int, len(b.Water))
+		for i, w := range b.Water {
+			// i 自底向上; 只有离瓶口不足visible层的才可见
+			if visible >= 0 && len(b.Water)-i > visible {
+				layers[i] = HiddenColor
+			} else {
+				layers[i] = w
+			}
+		}
+		obs.Bottles = append(obs.Bottles, layers)
+		obs.Collected = append(obs.Collected, b.Collected)
+	}
+	for _, bag := range g.Bags {
+		obs.BagColors = append(obs.BagColors, bag.Color)
+	}
+	return obs
+}
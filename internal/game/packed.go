@@ -0,0 +1,53 @@
+package game
+
+// 压缩的定长瓶子表示: 快照里每个瓶子不再是"一个对象加一个切片",
+// 而是一个纯值(定长数组+长度字节). copyGameState从每瓶两次分配降到
+// 整个局面一次, 求解器和CheckPossibleMoves的模拟不再冲击GC.
+
+// MaxCapacity 单个容器支持的最大容量, 受压缩表示的定长数组限制
+const MaxCapacity = 24
+
+// packedBottle 一个瓶子的紧凑表示. 颜色用colorByte的偏移编码,
+// 万能/隐藏这类负数特殊值也装得下.
+type packedBottle struct {
+	water    [MaxCapacity]uint8
+	length   uint8
+	capacity uint8
+	flags    uint8
+}
+
+const (
+	packedFlagJar       = 1 << 0
+	packedFlagCollected = 1 << 1
+	// 罐子变体占两个bit, 见jarkind.go
+	packedKindShift = 2
+	packedKindMask  = 3 << packedKindShift
+)
+
+// packBottle 把公开的Bottle压缩进定长表示
+func packBottle(b *Bottle) packedBottle {
+	p := packedBottle{capacity: uint8(b.Capacity), length: uint8(len(b.Water))}
+	if b.IsJar {
+		p.flags |= packedFlagJar
+	}
+	if b.Collected {
+		p.flags |= packedFlagCollected
+	}
+	p.flags |= uint8(b.Kind) << packedKindShift
+	for i, w := range b.Water {
+		p.water[i] = colorByte(w)
+	}
+	return p
+}
+
+// unpackInto 把压缩表示展开回Bottle, 复用已有的切片容量
+func (p *packedBottle) unpackInto(b *Bottle) {
+	b.Capacity = int(p.capacity)
+	b.IsJar = p.flags&packedFlagJar != 0
+	b.Collected = p.flags&packedFlagCollected != 0
+	b.Kind = JarKind((p.flags & packedKindMask) >> packedKindShift)
+	b.Water = b.Water[:0]
+	for i := 0; i < int(p.length); i++ {
+		b.Water = append(b.Water, colorFromByte(p.water[i]))
+	}
+}
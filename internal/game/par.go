@@ -0,0 +1,37 @@
+package game
+
+// 标准步数与打星: 每个谜题有一个par(求解器对初始局面给出的解长),
+// 通关后按用掉的步数对照par打1~3星. 打星口径和谜题包战报(pack)一致,
+// 前端不论走哪条通关路径, 评分都是同一套标准.
+
+// Par 本谜题的标准步数. 第一次调用时从初始局面(有走子历史时取
+// 第一条历史快照)跑一遍求解器并缓存; 小盘par就是最短解, 大盘
+// 求解器退化为启发式时是近似值. 求不出解时返回0.
+func (g *WaterBottleGame) Par() int {
+	if g.par > 0 {
+		return g.par
+	}
+	cur := g.copyGameState()
+	defer g.restoreGameState(cur)
+	if len(g.history) > 0 {
+		g.restoreGameState(g.history[0])
+	}
+	if moves, ok := g.Solve(); ok {
+		g.par = len(moves)
+	}
+	return g.par
+}
+
+// RateSolution 按一次通关用掉的步数打星: 不超par三星, 1.5倍par内
+// 两星, 其余(包括par算不出来时)一星.
+func (g *WaterBottleGame) RateSolution(movesUsed int) int {
+	par := g.Par()
+	switch {
+	case par > 0 && movesUsed <= par:
+		return 3
+	case par > 0 && movesUsed*2 <= par*3:
+		return 2
+	default:
+		return 1
+	}
+}
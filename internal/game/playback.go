@@ -0,0 +1,43 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// 解法回放: 把求解器给出的走子序列一步一步演出来, 每走一步交给
+// 渲染器展示一次, 演示程序用它实现"自动求解"的观赏模式.
+
+// Renderer 回放时的渲染器, 每走完一步被调用一次;
+// step从1数起, m是刚执行的那步.
+type Renderer interface {
+	RenderStep(g *WaterBottleGame, step int, m Move)
+}
+
+// RendererFunc 把普通函数适配成Renderer
+type RendererFunc func(g *WaterBottleGame, step int, m Move)
+
+// RenderStep 实现Renderer
+func (f RendererFunc) RenderStep(g *WaterBottleGame, step int, m Move) { f(g, step, m) }
+
+// PlaySolution 按顺序执行一份解法, 每步之间停顿delay, 每步走完后
+// 调用renderer展示局面. 中途某步不合法时停在那一步并报错, 已走的
+// 步数不回滚; renderer传nil则只执行不展示.
+func (g *WaterBottleGame) PlaySolution(moves []Move, delay time.Duration, renderer Renderer) error {
+	for i, m := range moves {
+		if err := g.cancelled(); err != nil {
+			return err
+		}
+		if i > 0 && delay > 0 {
+			time.Sleep(delay)
+		}
+		if _, err := g.Pour(m.From, m.To); err != nil {
+			return fmt.Errorf("回放第%d步 %s->%s 失败: %w",
+				i+1, g.ContainerName(m.From), g.ContainerName(m.To), err)
+		}
+		if renderer != nil {
+			renderer.RenderStep(g, i+1, m)
+		}
+	}
+	return nil
+}
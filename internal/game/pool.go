@@ -0,0 +1,24 @@
+package game
+
+import "sync"
+
+// 快照对象池: 模拟类代码(求解, 生成验证, 试探性走子)在一个作用域内
+// 反复"复制-走子-恢复", 这些快照生命周期明确, 不必每次都新分配.
+// 撤销历史和检查点这类要长期持有的快照仍然走copyGameState, 绝不能
+// 把还被引用的快照放回池里.
+
+var snapshotPool = sync.Pool{
+	New: func() interface{} { return &gameSnapshot{} },
+}
+
+// borrowSnapshot 从池里拿一份当前局面的快照, 用完必须releaseSnapshot
+func (g *WaterBottleGame) borrowSnapshot() *gameSnapshot {
+	snap := snapshotPool.Get().(*gameSnapshot)
+	g.fillSnapshot(snap)
+	return snap
+}
+
+// releaseSnapshot 把借来的快照还回池里, 之后不得再使用它
+func releaseSnapshot(snap *gameSnapshot) {
+	snapshotPool.Put(snap)
+}
@@ -0,0 +1,201 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 道具系统: 对局中可以主动使用的次数有限的道具. 内置四种(换顶/抽水/
+// 窥视/回溯), 都实现PowerUp接口, 外部包实现同一接口即可扩展新道具.
+// 除回溯外的道具在生效前压入撤销历史, 用错了可以撤销.
+
+// PowerUp 一种对局道具. targets是道具作用的容器下标,
+// 需要几个由具体道具决定, 不符合时返回错误且不改动局面.
+type PowerUp interface {
+	Name() string                                   // 道具名称
+	Description() string                            // 一句话说明
+	Apply(g *WaterBottleGame, targets ...int) error // 生效; 失败时不得改动局面
+}
+
+// PowerUpSlot 道具栏里的一格: 道具和剩余次数
+type PowerUpSlot struct {
+	Item PowerUp
+	Uses int
+}
+
+// ErrPowerUpUnknown 道具栏里没有这个道具
+var ErrPowerUpUnknown = errors.New("没有这个道具")
+
+// ErrPowerUpExhausted 道具次数已用完
+var ErrPowerUpExhausted = errors.New("道具次数已用完")
+
+// GrantPowerUp 往道具栏里加道具. 同名道具叠加次数.
+func (g *WaterBottleGame) GrantPowerUp(p PowerUp, uses int) {
+	if uses <= 0 {
+		return
+	}
+	for _, slot := range g.powerUps {
+		if slot.Item.Name() == p.Name() {
+			slot.Uses += uses
+			return
+		}
+	}
+	g.powerUps = append(g.powerUps, &PowerUpSlot{Item: p, Uses: uses})
+}
+
+// GrantDefaultPowerUps 发放标准道具组: 换顶x2, 抽水x1, 窥视x2, 回溯x1
+func (g *WaterBottleGame) GrantDefaultPowerUps() {
+	g.GrantPowerUp(SwapTopPowerUp{}, 2)
+	g.GrantPowerUp(RemoveTopPowerUp{}, 1)
+	g.GrantPowerUp(PeekPowerUp{}, 2)
+	g.GrantPowerUp(RewindPowerUp{}, 1)
+}
+
+// PowerUps 道具栏快照(含次数已用完的道具)
+func (g *WaterBottleGame) PowerUps() []PowerUpSlot {
+	var out []PowerUpSlot
+	for _, slot := range g.powerUps {
+		out = append(out, *slot)
+	}
+	return out
+}
+
+// UsePowerUp 按名称使用一个道具. 道具生效成功才扣次数.
+func (g *WaterBottleGame) UsePowerUp(name string, targets ...int) error {
+	for _, slot := range g.powerUps {
+		if slot.Item.Name() != name {
+			continue
+		}
+		if slot.Uses <= 0 {
+			return fmt.Errorf("%w: %s", ErrPowerUpExhausted, name)
+		}
+		if err := slot.Item.Apply(g, targets...); err != nil {
+			return err
+		}
+		slot.Uses--
+		g.logger.Logf("✨ 使用道具%q, 剩余%d次", name, slot.Uses)
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrPowerUpUnknown, name)
+}
+
+// powerUpBottle 道具目标下标的公共校验: 下标合法且容器未被收集
+func powerUpBottle(g *WaterBottleGame, i int) (*Bottle, error) {
+	if i < 0 || i >= len(g.Bottles) {
+		return nil, ErrInvalidIndex
+	}
+	b := g.Bottles[i]
+	if b.Collected {
+		return nil, fmt.Errorf("%w: %s", ErrCollectedBottle, g.ContainerName(i))
+	}
+	return b, nil
+}
+
+// SwapTopPowerUp 换顶: 交换两个容器的顶层水(各一格).
+// 无视颜色规则, 是打开僵局的主力道具.
+type SwapTopPowerUp struct{}
+
+// Name 道具名称
+func (SwapTopPowerUp) Name() string { return "换顶" }
+
+// Description 一句话说明
+func (SwapTopPowerUp) Description() string { return "交换两个容器的顶层水" }
+
+// Apply 需要两个不同的目标, 都不能为空
+func (SwapTopPowerUp) Apply(g *WaterBottleGame, targets ...int) error {
+	if len(targets) != 2 || targets[0] == targets[1] {
+		return fmt.Errorf("%w: 换顶需要两个不同的容器", ErrInvalidIndex)
+	}
+	a, err := powerUpBottle(g, targets[0])
+	if err != nil {
+		return err
+	}
+	b, err := powerUpBottle(g, targets[1])
+	if err != nil {
+		return err
+	}
+	if a.IsEmpty() || b.IsEmpty() {
+		return fmt.Errorf("%w: 换顶的两个容器都不能为空", ErrSourceEmpty)
+	}
+	g.pushHistory()
+	ai, bi := len(a.Water)-1, len(b.Water)-1
+	a.Water[ai], b.Water[bi] = b.Water[bi], a.Water[ai]
+	g.revealCurrentTops()
+	return nil
+}
+
+// RemoveTopPowerUp 抽水: 移除一个容器的顶层一格水.
+// 注意非袋子模式下抽掉的颜色会永远差一格装不满,
+// 只适合清理万能格或袋子模式下的残水.
+type RemoveTopPowerUp struct{}
+
+// Name 道具名称
+func (RemoveTopPowerUp) Name() string { return "抽水" }
+
+// Description 一句话说明
+func (RemoveTopPowerUp) Description() string { return "移除一个容器的顶层一格水" }
+
+// Apply 需要一个非空目标
+func (RemoveTopPowerUp) Apply(g *WaterBottleGame, targets ...int) error {
+	if len(targets) != 1 {
+		return fmt.Errorf("%w: 抽水需要一个容器", ErrInvalidIndex)
+	}
+	b, err := powerUpBottle(g, targets[0])
+	if err != nil {
+		return err
+	}
+	if b.IsEmpty() {
+		return ErrSourceEmpty
+	}
+	g.pushHistory()
+	b.Water = b.Water[:len(b.Water)-1]
+	g.revealCurrentTops()
+	return nil
+}
+
+// PeekPowerUp 窥视: 在隐藏层模式下揭示一个容器的全部水层.
+// 揭示和mystery.go里的规则一样是单调的, 撤销也不会收回.
+type PeekPowerUp struct{}
+
+// Name 道具名称
+func (PeekPowerUp) Name() string { return "窥视" }
+
+// Description 一句话说明
+func (PeekPowerUp) Description() string { return "揭示一个容器的全部隐藏水层" }
+
+// Apply 需要一个目标, 只在隐藏层模式下有意义
+func (PeekPowerUp) Apply(g *WaterBottleGame, targets ...int) error {
+	if !g.Mystery {
+		return fmt.Errorf("窥视只在隐藏层模式下可用")
+	}
+	if len(targets) != 1 {
+		return fmt.Errorf("%w: 窥视需要一个容器", ErrInvalidIndex)
+	}
+	b, err := powerUpBottle(g, targets[0])
+	if err != nil {
+		return err
+	}
+	g.revealRange(targets[0], 0, len(b.Water))
+	return nil
+}
+
+// RewindPowerUp 回溯: 免费回到最近一次被求解器确认可解的局面,
+// 不像普通撤销那样扣分. 底层就是UndoToLastCheckpoint.
+type RewindPowerUp struct{}
+
+// Name 道具名称
+func (RewindPowerUp) Name() string { return "回溯" }
+
+// Description 一句话说明
+func (RewindPowerUp) Description() string { return "免费回到最近确认可解的局面" }
+
+// Apply 不需要目标; 没有检查点时报错且不扣次数
+func (RewindPowerUp) Apply(g *WaterBottleGame, targets ...int) error {
+	if len(targets) != 0 {
+		return fmt.Errorf("%w: 回溯不需要目标", ErrInvalidIndex)
+	}
+	if !g.UndoToLastCheckpoint() {
+		return fmt.Errorf("还没有可回溯的检查点, 先用一次提示或求解")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package game
+
+// 长时操作的进度上报. 默认走日志行, UI要画进度条或服务端要打
+// 结构化日志时注册一个回调, 回调接管后不再输出默认日志.
+
+// ProgressFunc 进度回调, done从0递增到total
+type ProgressFunc func(done, total int)
+
+// SetProgressFunc 注册进度回调, 传nil恢复默认的日志输出.
+// 反向生成和验证过程都会经由它上报.
+func (g *WaterBottleGame) SetProgressFunc(fn ProgressFunc) {
+	g.progress = fn
+}
+
+// reportProgress 上报一次进度: 有回调时逐步上报,
+// 没有回调时每20步打一行日志, 维持原有的输出节奏.
+func (g *WaterBottleGame) reportProgress(label string, done, total int) {
+	if g.progress != nil {
+		g.progress(done, total)
+		return
+	}
+	if done > 0 && done%20 == 0 {
+		g.logger.Logf("  %s: %d/%d", label, done, total)
+	}
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"fmt"
+	"io"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// 分享码的二维码形式: 直播画面角落或线下纸面上贴一张图比贴一行
+// base64好用得多. 基于ShareCode编码, 扫出来的内容就是分享码,
+// 用"粘贴导入"开局. 盘面大时存档JSON会超出QR码的容量上限,
+// 这时报错而不是生成扫不出来的图.
+
+// qrPNGSize 导出PNG的边长像素数
+const qrPNGSize = 512
+
+// ExportQR 把当前局面的分享码画成QR码PNG写入w
+func (g *WaterBottleGame) ExportQR(w io.Writer) error {
+	code, err := g.ShareCode()
+	if err != nil {
+		return err
+	}
+	png, err := qrcode.Encode(code, qrcode.Medium, qrPNGSize)
+	if err != nil {
+		return fmt.Errorf("生成QR码失败(局面可能太大): %w", err)
+	}
+	_, err = w.Write(png)
+	return err
+}
+
+// ExportQRText 终端可打印的ASCII版QR码, 半格字符两行并一行
+func (g *WaterBottleGame) ExportQRText() (string, error) {
+	code, err := g.ShareCode()
+	if err != nil {
+		return "", err
+	}
+	q, err := qrcode.New(code, qrcode.Low)
+	if err != nil {
+		return "", fmt.Errorf("生成QR码失败(局面可能太大): %w", err)
+	}
+	return q.ToSmallString(false), nil
+}
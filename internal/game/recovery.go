@@ -0,0 +1,66 @@
+package game
+
+import "fmt"
+
+// RecoveryStrategy 死局自救策略
+type RecoveryStrategy int
+
+const (
+	RecoverUndoMoves RecoveryStrategy = iota // 回撤最近的几步, 直到重新有路可走
+	RecoverAddBottle                         // 追加一个空瓶
+	RecoverShuffle                           // 重洗未完成瓶子里的水
+)
+
+// RecoveryResult 自救的执行结果, UI可以据此告诉玩家发生了什么
+type RecoveryResult struct {
+	Strategy    RecoveryStrategy
+	UndoneMoves int    // RecoverUndoMoves时回撤的步数
+	Description string // 中文描述
+}
+
+// RecoverFromDeadlock 在死局时按指定策略自救.
+// 当前局面仍有可行操作时直接返回错误, 不做任何修改.
+func (g *WaterBottleGame) RecoverFromDeadlock(strategy RecoveryStrategy) (RecoveryResult, error) {
+	if g.IsWon() || len(g.CheckPossibleMoves()) > 0 {
+		return RecoveryResult{}, fmt.Errorf("当前不是死局, 不需要自救")
+	}
+	switch strategy {
+	case RecoverUndoMoves:
+		undone := 0
+		for len(g.CheckPossibleMoves()) == 0 {
+			if !g.Undo() {
+				break
+			}
+			undone++
+		}
+		if undone == 0 {
+			return RecoveryResult{}, fmt.Errorf("没有可以回撤的步数")
+		}
+		return RecoveryResult{
+			Strategy:    RecoverUndoMoves,
+			UndoneMoves: undone,
+			Description: fmt.Sprintf("回撤了最近%d步", undone),
+		}, nil
+	case RecoverAddBottle:
+		// 经济模式下加空瓶要花钱, 付不起时AddEmptyBottle会原地放弃,
+		// 不能对玩家谎报自救成功
+		if !g.canAfford(EmptyBottleCost) {
+			return RecoveryResult{}, fmt.Errorf("%w: 加空瓶需要%d金币, 余额%d", ErrNotEnoughCoins, EmptyBottleCost, g.Coins())
+		}
+		g.AddEmptyBottle()
+		return RecoveryResult{
+			Strategy:    RecoverAddBottle,
+			Description: "追加了一个空瓶",
+		}, nil
+	case RecoverShuffle:
+		if !g.canAfford(ShuffleCost) {
+			return RecoveryResult{}, fmt.Errorf("%w: 洗牌需要%d金币, 余额%d", ErrNotEnoughCoins, ShuffleCost, g.Coins())
+		}
+		g.ShuffleWater()
+		return RecoveryResult{
+			Strategy:    RecoverShuffle,
+			Description: "重洗了未完成瓶子里的水",
+		}, nil
+	}
+	return RecoveryResult{}, fmt.Errorf("未知的自救策略: %d", strategy)
+}
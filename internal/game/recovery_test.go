@@ -0,0 +1,53 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// deadlockedGame 构造一个没有任何可行操作的死局
+func deadlockedGame(t *testing.T) *WaterBottleGame {
+	t.Helper()
+	g, err := NewWaterBottleGameFromState(2, 2, 2, 0, 0, 0, false, 1, [][]int{{1, 2}, {2, 1}})
+	if err != nil {
+		t.Fatalf("构造死局失败: %v", err)
+	}
+	if len(g.CheckPossibleMoves()) != 0 {
+		t.Fatalf("测试局面应该是死局")
+	}
+	return g
+}
+
+// TestRecoverFailsWhenBroke 经济模式下金币不够时, 自救必须返回错误,
+// 不能一边扣费失败一边宣称"追加了一个空瓶"
+func TestRecoverFailsWhenBroke(t *testing.T) {
+	for _, strategy := range []RecoveryStrategy{RecoverAddBottle, RecoverShuffle} {
+		g := deadlockedGame(t)
+		g.SetEconomyMode(true)
+		before := g.GetState()
+		_, err := g.RecoverFromDeadlock(strategy)
+		if !errors.Is(err, ErrNotEnoughCoins) {
+			t.Errorf("策略%d没钱时应返回ErrNotEnoughCoins, 得到%v", strategy, err)
+		}
+		if len(g.GetState()) != len(before) || g.BottleCount != 2 {
+			t.Errorf("策略%d失败后不应改动局面", strategy)
+		}
+	}
+}
+
+// TestRecoverSpendsCoins 余额充足时自救成功并扣掉对应费用
+func TestRecoverSpendsCoins(t *testing.T) {
+	g := deadlockedGame(t)
+	g.SetEconomyMode(true)
+	g.AddCoins(EmptyBottleCost)
+	res, err := g.RecoverFromDeadlock(RecoverAddBottle)
+	if err != nil {
+		t.Fatalf("有钱时自救应成功: %v", err)
+	}
+	if res.Strategy != RecoverAddBottle || g.BottleCount != 3 {
+		t.Errorf("自救后应多出一个空瓶")
+	}
+	if g.Coins() != 0 {
+		t.Errorf("自救应扣掉%d金币, 余额还剩%d", EmptyBottleCost, g.Coins())
+	}
+}
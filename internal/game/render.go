@@ -0,0 +1,168 @@
+package game
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 文本渲染: 把局面画到任意io.Writer上, 缓冲区/文件/网络流都行.
+// PrintState是它到标准输出的便捷封装, 服务器端预览和渲染的
+// 黄金文件测试直接用Render.
+
+// RenderOptions 渲染选项
+type RenderOptions struct {
+	ShowBags  bool         // 是否渲染袋子和后续颜色预告
+	TrueColor bool         // 用ANSI真彩色块画水层, 见ansi.go
+	Style     DisplayStyle // 水层样式, 见displaystyle.go
+	Columnar  bool         // 竖管并排的紧凑布局, 大盘更易读
+	Width     int          // 终端宽度(列数), 0表示按COLUMNS环境变量/80
+}
+
+// DefaultRenderOptions 演示程序使用的默认渲染选项,
+// 终端支持时自动启用真彩
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{ShowBags: true, TrueColor: TrueColorSupported()}
+}
+
+// renderCell 一格水按选项渲染成字符串
+func (g *WaterBottleGame) renderCell(bottle, layer int, opts RenderOptions) string {
+	if !g.isRevealed(bottle, layer) {
+		return "❓"
+	}
+	c := g.Bottles[bottle].Water[layer]
+	if isBombColor(c) {
+		if opts.Style != StyleEmoji {
+			return "!"
+		}
+		return "💣"
+	}
+	if isFrozenColor(c) {
+		if opts.Style != StyleEmoji {
+			return "#"
+		}
+		return "🧊"
+	}
+	if opts.Style != StyleEmoji {
+		return styledCell(c, opts.Style)
+	}
+	if opts.TrueColor {
+		return ansiCell(c)
+	}
+	return getColorEmoji(c)
+}
+
+// Render 把当前局面写到w上
+func (g *WaterBottleGame) Render(w io.Writer, opts RenderOptions) {
+	fmt.Fprintf(w, "\n=== 当前状态 (第%d步, 种子%d) ===\n", g.MoveCount, g.seed)
+	if opts.Columnar {
+		g.renderColumnar(w, opts)
+	} else {
+		g.renderRows(w, opts)
+	}
+	if !opts.ShowBags {
+		return
+	}
+	for i, bag := range g.Bags {
+		if bag.Color == 0 {
+			fmt.Fprintf(w, "袋子%d: 已收集%d瓶, 完成\n", i, bag.Collected)
+		} else {
+			units, bottles := g.BagColorRemaining(i)
+			fmt.Fprintf(w, "袋子%d: 需要%s%s, 已收集%d瓶 (场上剩%d格, 还能凑%d瓶)\n",
+				i, getColorEmoji(bag.Color), getColorName(bag.Color), bag.Collected, units, bottles)
+		}
+	}
+	if next := g.PeekNextBagColors(3); len(next) > 0 {
+		var names []string
+		for _, c := range next {
+			names = append(names, getColorEmoji(c)+getColorName(c))
+		}
+		fmt.Fprintf(w, "接下来的袋子颜色: %s\n", strings.Join(names, " -> "))
+	}
+}
+
+// renderRows 一瓶一行的经典布局
+func (g *WaterBottleGame) renderRows(w io.Writer, opts RenderOptions) {
+	for i, b := range g.Bottles {
+		if b.Collected {
+			fmt.Fprintf(w, "%s: ✔ 已收集\n", g.ContainerName(i))
+			continue
+		}
+		var layers strings.Builder
+		for j := range b.Water {
+			layers.WriteString(g.renderCell(i, j, opts))
+		}
+		fmt.Fprintf(w, "%s: [%s] %s %d/%d\n", g.ContainerName(i), layers.String(), renderBar(len(b.Water), b.Capacity), len(b.Water), b.Capacity)
+	}
+}
+
+// columnarBottleWidth 紧凑布局里每根竖管占的列数: 两列水格加两壁加间隔
+const columnarBottleWidth = 5
+
+// renderColumnar 竖管并排的紧凑布局: 瓶子画成并排的管子, 按宽度自动
+// 换行, 20~30瓶的大盘一屏就能看全. 每格固定两列宽, 字母/纹理样式
+// 补一个空格对齐.
+func (g *WaterBottleGame) renderColumnar(w io.Writer, opts RenderOptions) {
+	width := opts.Width
+	if width <= 0 {
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+			width = n
+		} else {
+			width = 80
+		}
+	}
+	perRow := width / columnarBottleWidth
+	if perRow < 1 {
+		perRow = 1
+	}
+	for start := 0; start < len(g.Bottles); start += perRow {
+		end := start + perRow
+		if end > len(g.Bottles) {
+			end = len(g.Bottles)
+		}
+		maxCap := 0
+		for _, b := range g.Bottles[start:end] {
+			if b.Capacity > maxCap {
+				maxCap = b.Capacity
+			}
+		}
+		for row := maxCap - 1; row >= 0; row-- {
+			var line strings.Builder
+			for i := start; i < end; i++ {
+				b := g.Bottles[i]
+				if b.Collected || row >= b.Capacity {
+					line.WriteString("     ")
+					continue
+				}
+				if row < len(b.Water) {
+					line.WriteString("│" + g.columnarCell(i, row, opts) + "│ ")
+				} else {
+					line.WriteString("│  │ ")
+				}
+			}
+			fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+		}
+		var bottom, labels strings.Builder
+		for i := start; i < end; i++ {
+			if g.Bottles[i].Collected {
+				bottom.WriteString(" ✔   ")
+			} else {
+				bottom.WriteString("└──┘ ")
+			}
+			labels.WriteString(fmt.Sprintf("%-5d", i))
+		}
+		fmt.Fprintln(w, strings.TrimRight(bottom.String(), " "))
+		fmt.Fprintln(w, strings.TrimRight(labels.String(), " "))
+	}
+}
+
+// columnarCell 紧凑布局的一格, 固定补齐到两列宽
+func (g *WaterBottleGame) columnarCell(bottle, row int, opts RenderOptions) string {
+	s := g.renderCell(bottle, row, opts)
+	if opts.Style == StyleLetters || opts.Style == StylePatterns {
+		return s + " "
+	}
+	return s
+}
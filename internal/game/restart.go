@@ -0,0 +1,63 @@
+package game
+
+import "time"
+
+// 重做与重开: 撤销原来是单行道, 撤多了只能认; 重开更是只能换种子
+// 开新局, 因为生成出来的初始布局没人留着. 这里补上两块 —
+// Undo时把撤掉的状态垫进重做栈, 真实落子再清掉; 生成结束时用
+// markInitialState把初始布局钉住, Restart随时原样重来.
+
+// Redo 重做最近一次被撤销的操作, 没有可重做的内容时返回false
+func (g *WaterBottleGame) Redo() bool {
+	if len(g.redoStack) == 0 {
+		return false
+	}
+	snap := g.redoStack[len(g.redoStack)-1]
+	g.redoStack = g.redoStack[:len(g.redoStack)-1]
+	// 当前(撤销后)的状态回到走子历史里, 重做之后还能再撤销;
+	// historyLen由Undo弹出时重算, 这里不用管
+	cur := g.copyGameState()
+	g.restoreGameState(snap)
+	g.history = append(g.history, cur)
+	g.revealCurrentTops()
+	return true
+}
+
+// CanRedo 当前是否有可重做的操作
+func (g *WaterBottleGame) CanRedo() bool {
+	return len(g.redoStack) > 0
+}
+
+// captureInitialState 第一次真实落子前把当前局面钉成"初始布局",
+// Restart恢复到这里. 懒捕获的好处是生成后追加的注入(万能色/冰冻/
+// 炸弹)和开局洗牌都算进初始布局, 各生成入口也不用逐个埋点.
+func (g *WaterBottleGame) captureInitialState() {
+	if g.suppressEvents || g.initialSnap != nil || g.MoveCount != 0 {
+		return
+	}
+	g.initialSnap = g.copyGameState()
+}
+
+// Restart 回到初始布局原样重来: 走子历史, 重做栈和计时一并清零.
+// 中途载入的存档没有初始布局可回, 返回false.
+func (g *WaterBottleGame) Restart() bool {
+	if g.initialSnap == nil {
+		if g.MoveCount != 0 {
+			return false
+		}
+		// 还没走过子, 当前局面就是初始布局
+		g.captureInitialState()
+	}
+	g.restoreGameState(g.initialSnap)
+	g.redoStack = nil
+	g.startedAt = time.Now()
+	g.timeLossNotified = false
+	g.bombLossNotified = false
+	g.winRewardPaid = false
+	g.combo = 0
+	g.turnCompletions = 0
+	if !g.suppressEvents {
+		g.logger.Logf("🔄 重开本局, 回到初始布局")
+	}
+	return true
+}
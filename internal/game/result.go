@@ -0,0 +1,66 @@
+package game
+
+// ResultCode 对局结果代码. 服务器和前端应当匹配代码而不是解析中文文案.
+type ResultCode string
+
+const (
+	ResultPlaying         ResultCode = "PLAYING"           // 对局进行中
+	ResultWinAllSorted    ResultCode = "WIN_ALL_SORTED"    // 经典模式: 全部整理完毕
+	ResultWinAllCollected ResultCode = "WIN_ALL_COLLECTED" // 袋子模式: 所有颜色收集完毕
+	ResultLoseDeadlock    ResultCode = "LOSE_DEADLOCK"     // 没有任何可行操作
+	ResultLoseTimeout     ResultCode = "LOSE_TIMEOUT"      // 计时模式超时, 见challenge.go
+	ResultLoseMoveLimit   ResultCode = "LOSE_MOVE_LIMIT"   // 步数用尽, 见challenge.go
+	ResultLoseBombs       ResultCode = "LOSE_BOMBS"        // 炸弹爆炸, 见bomb.go
+)
+
+// IsWin 是否是获胜结果
+func (c ResultCode) IsWin() bool {
+	return c == ResultWinAllSorted || c == ResultWinAllCollected
+}
+
+// IsLose 是否是失败结果
+func (c ResultCode) IsLose() bool {
+	return c == ResultLoseDeadlock || c == ResultLoseTimeout || c == ResultLoseMoveLimit || c == ResultLoseBombs
+}
+
+// resultMessages 结果代码对应的中文展示文案
+var resultMessages = map[ResultCode]string{
+	ResultPlaying:         "对局进行中",
+	ResultWinAllSorted:    "🎉 恭喜获胜! 所有瓶子整理完毕",
+	ResultWinAllCollected: "🎉 恭喜获胜! 所有颜色收集完毕",
+	ResultLoseDeadlock:    "💀 没有可行的倒水操作了, 游戏结束",
+	ResultLoseTimeout:     "⏰ 时间用尽, 游戏结束",
+	ResultLoseMoveLimit:   "🚫 步数用尽, 游戏结束",
+	ResultLoseBombs:       "💥 炸弹爆炸了, 游戏结束",
+}
+
+// Message 返回结果代码的本地化展示文案
+func (c ResultCode) Message() string {
+	if msg, ok := resultMessages[c]; ok {
+		return msg
+	}
+	return string(c)
+}
+
+// Result 判定当前对局的结果代码
+func (g *WaterBottleGame) Result() ResultCode {
+	if g.IsWon() {
+		if g.UseBags {
+			return ResultWinAllCollected
+		}
+		return ResultWinAllSorted
+	}
+	if g.timedOut() {
+		return ResultLoseTimeout
+	}
+	if g.bombExploded() {
+		return ResultLoseBombs
+	}
+	if g.moveLimit > 0 && g.MoveCount >= g.moveLimit {
+		return ResultLoseMoveLimit
+	}
+	if !g.hasLegalMove() {
+		return ResultLoseDeadlock
+	}
+	return ResultPlaying
+}
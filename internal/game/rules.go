@@ -0,0 +1,27 @@
+package game
+
+// RuleSet 规则变体. 经典倒水和球排序共用同一套引擎,
+// 差别集中在validatePour对单次搬运量的计算上.
+type RuleSet int
+
+const (
+	// RuleWaterSort 经典倒水: 顶部整段同色水一起倒
+	RuleWaterSort RuleSet = iota
+	// RuleBallSort 球排序: 单位是球, 一次只能移动一颗,
+	// 目标必须是空的或顶部同色
+	RuleBallSort
+)
+
+// String 规则变体的中文名称
+func (r RuleSet) String() string {
+	if r == RuleBallSort {
+		return "球排序"
+	}
+	return "倒水"
+}
+
+// SetRuleSet 切换规则变体. 应在开局时设置; 求解器, 提示和重放
+// 都通过Pour走子, 自动遵守当前规则.
+func (g *WaterBottleGame) SetRuleSet(r RuleSet) {
+	g.Rules = r
+}
@@ -0,0 +1,49 @@
+package game
+
+import "fmt"
+
+// 沙盒模式: UI让玩家随便试几步, 然后整体保留或整体反悔. 沙盒期间
+// 走子一切照常(倒水, 袋子收集, 金币, 事件都真实发生), 放弃时连同
+// 过程统计一起原子回滚, 像这几步从来没走过一样; 提交则把试出来的
+// 这条线原样并入对局. 隐藏层模式下看到的信息不回滚, 看过就是看过.
+
+// BeginSandbox 进入沙盒, 记下当前局面作为反悔点. 沙盒不能嵌套,
+// 想换个反悔点先提交或放弃当前沙盒.
+func (g *WaterBottleGame) BeginSandbox() error {
+	if g.sandboxBase != nil {
+		return fmt.Errorf("已经在沙盒里了, 先提交或放弃")
+	}
+	g.sandboxBase = g.copyGameState()
+	g.sandboxStats = g.stats
+	g.logger.Logf("🧪 进入沙盒, 随便试, 放弃时整体回滚")
+	return nil
+}
+
+// CommitSandbox 保留沙盒里走的所有步, 退出沙盒
+func (g *WaterBottleGame) CommitSandbox() error {
+	if g.sandboxBase == nil {
+		return fmt.Errorf("不在沙盒里")
+	}
+	g.sandboxBase = nil
+	g.logger.Logf("🧪 沙盒已提交, 试过的步数并入对局")
+	return nil
+}
+
+// DiscardSandbox 整体回滚到进沙盒时的局面, 退出沙盒.
+// 过程统计也一并还原, 沙盒里的撤销/提示不计入档案.
+func (g *WaterBottleGame) DiscardSandbox() error {
+	if g.sandboxBase == nil {
+		return fmt.Errorf("不在沙盒里")
+	}
+	g.restoreGameState(g.sandboxBase)
+	g.stats = g.sandboxStats
+	g.sandboxBase = nil
+	g.revealCurrentTops()
+	g.logger.Logf("🧪 沙盒已放弃, 局面回滚")
+	return nil
+}
+
+// InSandbox 当前是否在沙盒里
+func (g *WaterBottleGame) InSandbox() bool {
+	return g.sandboxBase != nil
+}
@@ -0,0 +1,220 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// gameSave 存档的JSON结构
+type gameSave struct {
+	Seed            int64     `json:"seed"`
+	BottleCount     int       `json:"bottleCount"`
+	Capacity        int       `json:"capacity"`
+	ColorCount      int       `json:"colorCount"`
+	EmptyBottles    int       `json:"emptyBottles"`
+	JarCount        int       `json:"jarCount"`
+	JarCapacity     int       `json:"jarCapacity"`
+	UseBags         bool      `json:"useBags"`
+	Rules           RuleSet   `json:"rules,omitempty"`
+	MoveCount       int       `json:"moveCount"`
+	Bottles         [][]int   `json:"bottles"`
+	Capacities      []int     `json:"capacities"`
+	Collected       []bool    `json:"collected"`
+	Kinds           []JarKind `json:"jarKinds,omitempty"`
+	Bags            []*Bag    `json:"bags,omitempty"`
+	CollectedColors []int     `json:"collectedColors,omitempty"`
+	BombFuse        int       `json:"bombFuse,omitempty"`
+	Mystery         bool      `json:"mystery,omitempty"`
+	Revealed        [][]bool  `json:"revealed,omitempty"`
+	MoveLimit       int       `json:"moveLimit,omitempty"`
+	TimeLeftMs      int64     `json:"timeLeftMs,omitempty"` // 倒计时剩余毫秒, 存档期间计时暂停
+	Economy         bool      `json:"economyMode,omitempty"`
+	Coins           int       `json:"coins,omitempty"`
+}
+
+// SaveData 把当前局面序列化为JSON存档
+func (g *WaterBottleGame) SaveData() ([]byte, error) {
+	save := &gameSave{
+		Seed:         g.seed,
+		BottleCount:  g.BottleCount,
+		Capacity:     g.Capacity,
+		ColorCount:   g.ColorCount,
+		EmptyBottles: g.EmptyBottles,
+		JarCount:     g.JarCount,
+		JarCapacity:  g.JarCapacity,
+		UseBags:      g.UseBags,
+		Rules:        g.Rules,
+		MoveCount:    g.MoveCount,
+		BombFuse:     g.bombFuse,
+		Mystery:      g.Mystery,
+		MoveLimit:    g.moveLimit,
+		Economy:      g.economyMode,
+		Coins:        g.coins,
+	}
+	for _, row := range g.revealed {
+		save.Revealed = append(save.Revealed, append([]bool(nil), row...))
+	}
+	if !g.deadline.IsZero() {
+		// 存剩余时长而不是绝对时刻, 载入时接着倒数; 已经超时的局
+		// 存个负数, 载入后照样是超时
+		if save.TimeLeftMs = time.Until(g.deadline).Milliseconds(); save.TimeLeftMs == 0 {
+			save.TimeLeftMs = -1
+		}
+	}
+	for _, b := range g.Bottles {
+		save.Bottles = append(save.Bottles, append([]int(nil), b.Water...))
+		save.Capacities = append(save.Capacities, b.Capacity)
+		save.Collected = append(save.Collected, b.Collected)
+		save.Kinds = append(save.Kinds, b.Kind)
+	}
+	for _, bag := range g.Bags {
+		save.Bags = append(save.Bags, &Bag{Color: bag.Color, Collected: bag.Collected})
+	}
+	for c := range g.collectedColors {
+		save.CollectedColors = append(save.CollectedColors, c)
+	}
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// NewWaterBottleGameFromState 用给定的初始局面直接构造对局, 不重新生成.
+// 谜题包/外部导入的关卡用它还原固定局面.
+func NewWaterBottleGameFromState(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool, seed int64, bottles [][]int) (*WaterBottleGame, error) {
+	if len(bottles) != bottleCount+jarCount {
+		return nil, fmt.Errorf("局面包含%d个容器, 与参数要求的%d个不符", len(bottles), bottleCount+jarCount)
+	}
+	g := &WaterBottleGame{
+		BottleCount:     bottleCount,
+		Capacity:        capacity,
+		ColorCount:      colorCount,
+		EmptyBottles:    emptyBottles,
+		JarCount:        jarCount,
+		JarCapacity:     jarCapacity,
+		UseBags:         useBags,
+		collectedColors: make(map[int]bool),
+		rng:             rand.New(rand.NewSource(seed)),
+		seed:            seed,
+		audit:           RandAuditLog{Seed: seed},
+		logger:          SilentLogger,
+		scoring:         DefaultScoringConfig(),
+	}
+	for i, water := range bottles {
+		cap := capacity
+		if i >= bottleCount {
+			cap = jarCapacity
+		}
+		if len(water) > cap {
+			return nil, fmt.Errorf("容器%d装了%d层水, 超过容量%d", i, len(water), cap)
+		}
+		g.Bottles = append(g.Bottles, &Bottle{
+			Water:    append([]int(nil), water...),
+			Capacity: cap,
+			IsJar:    i >= bottleCount,
+		})
+	}
+	if useBags {
+		bag := &Bag{}
+		g.Bags = append(g.Bags, bag)
+		g.initBagQueue()
+		bag.Color = g.nextBagColor()
+	}
+	return g, nil
+}
+
+// LoadGameFromData 从JSON存档还原一局游戏
+func LoadGameFromData(data []byte) (*WaterBottleGame, error) {
+	var save gameSave
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, fmt.Errorf("存档解析失败: %w", err)
+	}
+	if len(save.Bottles) == 0 {
+		// 任意JSON对象都能解析成全零的gameSave, 空容器列表说明
+		// 粘贴的根本不是存档, 别开出一个没有瓶子的"对局"
+		return nil, fmt.Errorf("存档损坏: 里面没有任何容器")
+	}
+	if len(save.Bottles) != save.BottleCount+save.JarCount {
+		return nil, fmt.Errorf("存档损坏: 容器数量不一致")
+	}
+	if len(save.Capacities) != len(save.Bottles) {
+		return nil, fmt.Errorf("存档损坏: 容量表有%d项, 容器却有%d个", len(save.Capacities), len(save.Bottles))
+	}
+	for i, water := range save.Bottles {
+		cap := save.Capacities[i]
+		if cap <= 0 || cap > MaxCapacity {
+			return nil, fmt.Errorf("存档损坏: 容器%d的容量%d不在1~%d范围内", i, cap, MaxCapacity)
+		}
+		if len(water) > cap {
+			return nil, fmt.Errorf("存档损坏: 容器%d装了%d层水, 超过容量%d", i, len(water), cap)
+		}
+	}
+	g := &WaterBottleGame{
+		BottleCount:     save.BottleCount,
+		Capacity:        save.Capacity,
+		ColorCount:      save.ColorCount,
+		EmptyBottles:    save.EmptyBottles,
+		JarCount:        save.JarCount,
+		JarCapacity:     save.JarCapacity,
+		UseBags:         save.UseBags,
+		Rules:           save.Rules,
+		MoveCount:       save.MoveCount,
+		Mystery:         save.Mystery,
+		moveLimit:       save.MoveLimit,
+		economyMode:     save.Economy,
+		coins:           save.Coins,
+		collectedColors: make(map[int]bool),
+		rng:             rand.New(rand.NewSource(save.Seed)),
+		seed:            save.Seed,
+		audit:           RandAuditLog{Seed: save.Seed},
+		logger:          SilentLogger,
+		scoring:         DefaultScoringConfig(),
+		startedAt:       time.Now(),
+	}
+	if save.BombFuse > 0 {
+		g.bombFuse = save.BombFuse
+	}
+	for i, water := range save.Bottles {
+		w := append([]int(nil), water...)
+		if save.BombFuse <= 0 {
+			// 旧存档里没有引信字段: 把混进来的炸弹格就地拆掉,
+			// 否则bombExploded一看"有炸弹且引信为0"就把刚载入的
+			// 对局判负
+			for j, v := range w {
+				w[j] = bombBase(v)
+			}
+		}
+		b := &Bottle{
+			Water:    w,
+			Capacity: save.Capacities[i],
+			IsJar:    i >= save.BottleCount,
+		}
+		if i < len(save.Collected) {
+			b.Collected = save.Collected[i]
+		}
+		if i < len(save.Kinds) {
+			b.Kind = save.Kinds[i]
+		}
+		g.Bottles = append(g.Bottles, b)
+	}
+	for _, bag := range save.Bags {
+		g.Bags = append(g.Bags, &Bag{Color: bag.Color, Collected: bag.Collected})
+	}
+	for _, c := range save.CollectedColors {
+		g.collectedColors[c] = true
+	}
+	if save.UseBags {
+		// 重建颜色队列; 已收集和已在袋子上的颜色出队时会被跳过
+		g.initBagQueue()
+	}
+	for _, row := range save.Revealed {
+		g.revealed = append(g.revealed, append([]bool(nil), row...))
+	}
+	if g.Mystery && len(save.Revealed) == 0 {
+		// 旧档只存了开关没存揭示矩阵, 按重新开局的口径只亮出顶层
+		g.revealCurrentTops()
+	}
+	if save.TimeLeftMs != 0 {
+		g.deadline = time.Now().Add(time.Duration(save.TimeLeftMs) * time.Millisecond)
+	}
+	return g, nil
+}
@@ -0,0 +1,126 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSaveLoadRoundTrip 存档再载入后局面应该和原局完全一致
+func TestSaveLoadRoundTrip(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 1, 6, false, 42)
+	if m, ok := g.GetHint(); ok {
+		if _, err := g.Pour(m.From, m.To); err != nil {
+			t.Fatalf("铺垫走子失败: %v", err)
+		}
+	}
+	data, err := g.SaveData()
+	if err != nil {
+		t.Fatalf("存档失败: %v", err)
+	}
+	loaded, err := LoadGameFromData(data)
+	if err != nil {
+		t.Fatalf("载入失败: %v", err)
+	}
+	if loaded.MoveCount != g.MoveCount || loaded.Seed() != g.Seed() {
+		t.Errorf("载入后步数/种子不一致: %d/%d vs %d/%d",
+			loaded.MoveCount, loaded.Seed(), g.MoveCount, g.Seed())
+	}
+	if loaded.CanonicalKey() != g.CanonicalKey() {
+		t.Errorf("载入后的局面与原局不一致")
+	}
+	if loaded.scoring != DefaultScoringConfig() {
+		t.Errorf("载入的对局应带默认计分配置, 得到%+v", loaded.scoring)
+	}
+}
+
+// TestSaveLoadKeepsBombFuse 炸弹局存档再载入后引信不归零,
+// 对局不能一载入就被bombExploded判负
+func TestSaveLoadKeepsBombFuse(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 0, 0, false, 11)
+	if g.InjectBombs(1, 20) != 1 {
+		t.Fatalf("埋炸弹失败")
+	}
+	data, err := g.SaveData()
+	if err != nil {
+		t.Fatalf("存档失败: %v", err)
+	}
+	loaded, err := LoadGameFromData(data)
+	if err != nil {
+		t.Fatalf("载入失败: %v", err)
+	}
+	if loaded.BombFuse() != 20 {
+		t.Errorf("载入后引信应是20, 得到%d", loaded.BombFuse())
+	}
+	if r := loaded.Result(); r != ResultPlaying {
+		t.Errorf("载入后的炸弹局应还在进行中, 得到%s", r)
+	}
+}
+
+// TestLoadStripsBombsWithoutFuse 没带引信字段的旧存档里混着炸弹格时,
+// 载入要把炸弹就地拆掉而不是直接判负
+func TestLoadStripsBombsWithoutFuse(t *testing.T) {
+	data := `{"bottleCount":2,"capacity":2,"colorCount":1,
+		"bottles":[[1,201],[]],"capacities":[2,2]}`
+	loaded, err := LoadGameFromData([]byte(data))
+	if err != nil {
+		t.Fatalf("载入失败: %v", err)
+	}
+	if r := loaded.Result(); r == ResultLoseBombs {
+		t.Errorf("旧存档不应一载入就因炸弹判负")
+	}
+	if got := loaded.Bottles[0].Water[1]; got != 1 {
+		t.Errorf("炸弹格应被拆成底色1, 得到%d", got)
+	}
+}
+
+// TestSaveLoadKeepsModes 隐藏层/步数上限/倒计时/经济状态都要跟着
+// 存档走, 不能靠存读档白拿全图或甩掉限制
+func TestSaveLoadKeepsModes(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 0, 0, false, 13)
+	g.SetMysteryMode(true)
+	g.SetMoveLimit(30)
+	g.SetTimeLimit(time.Hour)
+	g.SetEconomyMode(true)
+	g.AddCoins(77)
+	data, err := g.SaveData()
+	if err != nil {
+		t.Fatalf("存档失败: %v", err)
+	}
+	loaded, err := LoadGameFromData(data)
+	if err != nil {
+		t.Fatalf("载入失败: %v", err)
+	}
+	if !loaded.Mystery {
+		t.Errorf("隐藏层模式丢了")
+	}
+	if !reflect.DeepEqual(loaded.revealed, g.revealed) {
+		t.Errorf("揭示矩阵不一致: %v vs %v", loaded.revealed, g.revealed)
+	}
+	if loaded.moveLimit != 30 {
+		t.Errorf("步数上限应是30, 得到%d", loaded.moveLimit)
+	}
+	if !loaded.economyMode || loaded.Coins() != 77 {
+		t.Errorf("经济状态丢了: mode=%v coins=%d", loaded.economyMode, loaded.Coins())
+	}
+	if left := time.Until(loaded.deadline); left <= 50*time.Minute || left > time.Hour {
+		t.Errorf("倒计时剩余应接近1小时, 得到%v", left)
+	}
+}
+
+// TestLoadGameRejectsCorruptSaves 缺字段/越界的存档JSON要报错而不是panic
+func TestLoadGameRejectsCorruptSaves(t *testing.T) {
+	cases := map[string]string{
+		"不是JSON": `{"bottleCount":`,
+		"容器数量不符": `{"bottleCount":3,"capacity":4,"bottles":[[1],[1]],"capacities":[4,4]}`,
+		"缺容量表":   `{"bottleCount":2,"capacity":4,"bottles":[[1,1,1,1],[]],"capacities":[4]}`,
+		"容量为零":   `{"bottleCount":2,"capacity":4,"bottles":[[],[]],"capacities":[0,4]}`,
+		"容量超上限":  `{"bottleCount":2,"capacity":4,"bottles":[[],[]],"capacities":[99,4]}`,
+		"水深超容量":  `{"bottleCount":2,"capacity":2,"bottles":[[1,1,1],[]],"capacities":[2,2]}`,
+	}
+	for name, data := range cases {
+		if _, err := LoadGameFromData([]byte(data)); err == nil {
+			t.Errorf("%s的存档应该报错, 却载入成功", name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package game
+
+// 计分系统: 完成瓶子得基础分, 连续回合都有完成时连击倍率递增,
+// 撤销和提示扣分. 分数是单向累计的, 撤销不会把已得的分退回去,
+// 只按惩罚扣除.
+
+// ScoringConfig 计分模型, 可按产品需要整体替换
+type ScoringConfig struct {
+	BottlePoints int // 每完成一瓶的基础分
+	MaxCombo     int // 连击倍率上限
+	UndoPenalty  int // 每次撤销扣的分
+	HintPenalty  int // 每次提示扣的分
+}
+
+// DefaultScoringConfig 默认计分: 一瓶100分, 连击最高5倍,
+// 撤销扣10分, 提示扣20分
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{BottlePoints: 100, MaxCombo: 5, UndoPenalty: 10, HintPenalty: 20}
+}
+
+// SetScoringConfig 替换计分模型, 只影响之后的得分
+func (g *WaterBottleGame) SetScoringConfig(cfg ScoringConfig) {
+	g.scoring = cfg
+}
+
+// Score 当前累计分数
+func (g *WaterBottleGame) Score() int {
+	return g.score
+}
+
+// Combo 当前连击数(连续有完成的回合数), 0表示连击已断
+func (g *WaterBottleGame) Combo() int {
+	return g.combo
+}
+
+// settleTurnScore 一个回合结束时结算得分: 本回合有完成则连击加一,
+// 按 基础分*完成数*连击 计入; 没有完成则连击清零. 模拟回合不结算.
+func (g *WaterBottleGame) settleTurnScore() {
+	if g.suppressEvents {
+		return
+	}
+	if g.turnCompletions == 0 {
+		g.combo = 0
+		return
+	}
+	g.combo++
+	multiplier := g.combo
+	if g.scoring.MaxCombo > 0 && multiplier > g.scoring.MaxCombo {
+		multiplier = g.scoring.MaxCombo
+	}
+	g.addScore(g.scoring.BottlePoints * g.turnCompletions * multiplier)
+	g.turnCompletions = 0
+}
+
+// addScore 调整分数并广播事件, 分数不会扣成负数
+func (g *WaterBottleGame) addScore(delta int) {
+	if delta == 0 {
+		return
+	}
+	g.score += delta
+	if g.score < 0 {
+		g.score = 0
+	}
+	g.emit(Event{Type: EventScoreChanged, Bottle: -1, Bag: -1, Score: g.score})
+}
@@ -0,0 +1,38 @@
+package game
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// sharePrefix 分享码的版本前缀, 格式变化时递增版本号
+const sharePrefix = "WSG1:"
+
+// ShareCode 把当前局面编码成一行可粘贴的分享码: 版本前缀加上
+// 存档JSON的base64. 不含换行和空格, 方便在聊天群里整行复制.
+func (g *WaterBottleGame) ShareCode() (string, error) {
+	data, err := g.SaveData()
+	if err != nil {
+		return "", err
+	}
+	return sharePrefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ImportShared 从一行粘贴的内容还原对局: 既接受ShareCode生成的分享码,
+// 也接受直接粘贴的存档JSON. 内容不合法时报错而不是开出坏局.
+func ImportShared(line string) (*WaterBottleGame, error) {
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, sharePrefix):
+		data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(line, sharePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("分享码解码失败: %w", err)
+		}
+		return LoadGameFromData(data)
+	case strings.HasPrefix(line, "{"):
+		return LoadGameFromData([]byte(line))
+	default:
+		return nil, fmt.Errorf("粘贴的内容既不是分享码也不是存档JSON")
+	}
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// TestShareCodeRoundTrip 分享码导入后应还原出同一个局面
+func TestShareCodeRoundTrip(t *testing.T) {
+	g := NewWaterBottleGameWithSeed(6, 4, 4, 2, 0, 0, false, 7)
+	code, err := g.ShareCode()
+	if err != nil {
+		t.Fatalf("生成分享码失败: %v", err)
+	}
+	imported, err := ImportShared(code)
+	if err != nil {
+		t.Fatalf("导入分享码失败: %v", err)
+	}
+	if imported.CanonicalKey() != g.CanonicalKey() {
+		t.Errorf("导入的局面与原局不一致")
+	}
+}
+
+// TestImportSharedRejectsBadPaste 用户粘贴的任意内容都不能让导入崩溃,
+// 不合法时必须返回错误
+func TestImportSharedRejectsBadPaste(t *testing.T) {
+	cases := map[string]string{
+		"随便一句话":     "你好世界",
+		"坏base64":   sharePrefix + "!!!!",
+		"base64非存档": sharePrefix + base64.RawURLEncoding.EncodeToString([]byte("not json")),
+		"无关JSON":    `{"foo":1}`,
+		"越界存档JSON":  `{"bottleCount":1,"capacity":4,"bottles":[[1,1,1,1,1,1]],"capacities":[4]}`,
+	}
+	for name, line := range cases {
+		if _, err := ImportShared(line); err == nil {
+			t.Errorf("%s应该报错, 却导入成功", name)
+		}
+	}
+}
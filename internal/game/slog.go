@@ -0,0 +1,40 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// slog桥接: 引擎内部的诊断输出(生成进度/收集通知/警告)统一走Logger
+// 接口, 这里把它接到log/slog上. 库的使用方通过slog的Handler控制
+// 级别, 格式和丢弃策略, 不需要引擎再提供开关.
+
+// slogAdapter 把Logger的Logf转成slog记录, 附带对局的结构化字段
+type slogAdapter struct {
+	g *WaterBottleGame
+	l *slog.Logger
+}
+
+// Logf 实现Logger. 警告类消息(以⚠️或❌开头)归Warn级别, 其余Info.
+func (a slogAdapter) Logf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	level := slog.LevelInfo
+	if strings.HasPrefix(msg, "⚠️") || strings.HasPrefix(msg, "❌") {
+		level = slog.LevelWarn
+	}
+	a.l.Log(context.Background(), level, msg,
+		slog.Int64("seed", a.g.seed),
+		slog.Int("move", a.g.MoveCount))
+}
+
+// SetSlog 让引擎消息经slog输出, 每条记录带上种子和当前步数字段;
+// 传nil恢复静默.
+func (g *WaterBottleGame) SetSlog(l *slog.Logger) {
+	if l == nil {
+		g.logger = SilentLogger
+		return
+	}
+	g.logger = slogAdapter{g: g, l: l}
+}
@@ -0,0 +1,210 @@
+package game
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// solverBudget 求解器的算法选择和状态预算
+type solverBudget struct {
+	algorithm string // "bfs" / "bestfirst" / "greedy"
+	maxStates int
+}
+
+// chooseSolverBudget 按棋盘规模 N*M*K 选择算法和预算, 保证Hint/Solve
+// 的响应时间可控: 小盘BFS求最短解, 中盘最佳优先搜索, 大盘走置换表
+// 搜索(见zobrist.go), 判重是常数成本, 预算可以放到百万级.
+func (g *WaterBottleGame) chooseSolverBudget() solverBudget {
+	size := g.BottleCount * g.Capacity * g.ColorCount
+	switch {
+	case size <= 150:
+		return solverBudget{algorithm: "bfs", maxStates: maxSolverStates}
+	case size <= 400:
+		return solverBudget{algorithm: "bestfirst", maxStates: 5 * maxSolverStates}
+	default:
+		return solverBudget{algorithm: "transposition", maxStates: 2000 * maxSolverStates}
+	}
+}
+
+// stateScore 局面的启发式评分, 越小越接近完成:
+// 统计每个容器内的颜色分段数, 再惩罚非单色的容器.
+func (g *WaterBottleGame) stateScore() int {
+	score := 0
+	for _, b := range g.Bottles {
+		if b.Collected || b.IsEmpty() {
+			continue
+		}
+		segments := 1
+		for i := 1; i < len(b.Water); i++ {
+			if b.Water[i] != b.Water[i-1] {
+				segments++
+			}
+		}
+		score += segments - 1
+		if segments > 1 {
+			score += 2
+		}
+	}
+	return score
+}
+
+// searchNode 最佳优先搜索的节点
+type searchNode struct {
+	snap  *gameSnapshot
+	path  []Move
+	score int
+}
+
+// nodeHeap 按score从小到大的优先队列
+type nodeHeap []*searchNode
+
+func (h nodeHeap) Len() int            { return len(h) }
+func (h nodeHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(*searchNode)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// solveBFS 宽度优先搜索, 找到的解一定最短
+func (g *WaterBottleGame) solveBFS(maxStates int) ([]Move, bool) {
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+
+	visited := make(map[string]bool)
+	queue := []*searchNode{{snap: start}}
+	explored := 0
+	for len(queue) > 0 && explored < maxStates {
+		if g.cancelled() != nil {
+			return nil, false
+		}
+		cur := queue[0]
+		queue = queue[1:]
+		g.restoreGameState(cur.snap)
+		if g.IsWon() {
+			// 顺带记录检查点: 起始局面被证实可解
+			g.safeCheckpoint = start
+			return cur.path, true
+		}
+		sig := g.CanonicalKey()
+		if visited[sig] {
+			continue
+		}
+		visited[sig] = true
+		explored++
+		for from := 0; from < len(g.Bottles); from++ {
+			for to := 0; to < len(g.Bottles); to++ {
+				if from == to {
+					continue
+				}
+				g.restoreGameState(cur.snap)
+				if _, err := g.Pour(from, to); err == nil {
+					path := append(append([]Move(nil), cur.path...), Move{From: from, To: to})
+					queue = append(queue, &searchNode{snap: g.copyGameState(), path: path})
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// solveBestFirst 启发式最佳优先搜索: 不保证最短, 但同样预算下走得更深
+func (g *WaterBottleGame) solveBestFirst(maxStates int) ([]Move, bool) {
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+
+	visited := make(map[string]bool)
+	h := &nodeHeap{{snap: start, score: g.stateScore()}}
+	explored := 0
+	for h.Len() > 0 && explored < maxStates {
+		if g.cancelled() != nil {
+			return nil, false
+		}
+		cur := heap.Pop(h).(*searchNode)
+		g.restoreGameState(cur.snap)
+		if g.IsWon() {
+			g.safeCheckpoint = start
+			return cur.path, true
+		}
+		sig := g.CanonicalKey()
+		if visited[sig] {
+			continue
+		}
+		visited[sig] = true
+		explored++
+		for from := 0; from < len(g.Bottles); from++ {
+			for to := 0; to < len(g.Bottles); to++ {
+				if from == to {
+					continue
+				}
+				g.restoreGameState(cur.snap)
+				if _, err := g.Pour(from, to); err == nil {
+					path := append(append([]Move(nil), cur.path...), Move{From: from, To: to})
+					heap.Push(h, &searchNode{snap: g.copyGameState(), path: path, score: g.stateScore() + len(path)})
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// solveGreedy 纯启发式走子: 每步挑评分最好的倒水, 不回溯.
+// 大盘上秒出结果, 但可能走不到终点.
+func (g *WaterBottleGame) solveGreedy(maxMoves int) ([]Move, bool) {
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+
+	seen := map[string]bool{g.CanonicalKey(): true}
+	var path []Move
+	for step := 0; step < maxMoves; step++ {
+		if g.cancelled() != nil {
+			return nil, false
+		}
+		if g.IsWon() {
+			g.safeCheckpoint = start
+			return path, true
+		}
+		type cand struct {
+			m     Move
+			score int
+		}
+		var cands []cand
+		before := g.borrowSnapshot()
+		for from := 0; from < len(g.Bottles); from++ {
+			for to := 0; to < len(g.Bottles); to++ {
+				if from == to {
+					continue
+				}
+				g.restoreGameState(before)
+				if _, err := g.Pour(from, to); err != nil {
+					continue
+				}
+				sig := g.CanonicalKey()
+				if seen[sig] {
+					continue
+				}
+				cands = append(cands, cand{m: Move{From: from, To: to}, score: g.stateScore()})
+			}
+		}
+		g.restoreGameState(before)
+		releaseSnapshot(before)
+		if len(cands) == 0 {
+			return nil, false
+		}
+		sort.Slice(cands, func(i, j int) bool { return cands[i].score < cands[j].score })
+		best := cands[0]
+		if _, err := g.Pour(best.m.From, best.m.To); err != nil {
+			return nil, false
+		}
+		seen[g.CanonicalKey()] = true
+		path = append(path, best.m)
+	}
+	if g.IsWon() {
+		return path, true
+	}
+	return nil, false
+}
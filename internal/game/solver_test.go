@@ -0,0 +1,102 @@
+package game
+
+import "testing"
+
+// 差分测试用的求解预算: 给得足够大, 小盘上求解失败只能是bug而不是预算不够
+const diffTestBudget = 20000
+
+// applySolution 在局面上重放一条解法并检查能否走到胜利, 重放结束后恢复原局面
+func applySolution(t *testing.T, g *WaterBottleGame, path []Move) bool {
+	t.Helper()
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	for i, m := range path {
+		if _, err := g.Pour(m.From, m.To); err != nil {
+			t.Errorf("解法第%d步 %d->%d 不合法: %v", i+1, m.From, m.To, err)
+			return false
+		}
+	}
+	return g.IsWon()
+}
+
+// TestSolversAgreeOnSeededPuzzles 差分测试: 在大量种子谜题上交叉验证
+// 三个独立的可解性来源 —— 反向生成(构造上保证可解), 精确求解器(BFS),
+// 启发式求解器(最佳优先). 任何一方意见不一致都说明搜索子系统有bug.
+func TestSolversAgreeOnSeededPuzzles(t *testing.T) {
+	params := []struct {
+		name                                      string
+		bottles, cap, colors, empty, jars, jarCap int
+	}{
+		{"小盘", 5, 4, 3, 2, 0, 0},
+		{"中盘", 6, 4, 4, 2, 0, 0},
+		{"带罐子", 4, 4, 2, 2, 1, 6},
+	}
+	perCase := 700
+	if testing.Short() {
+		perCase = 60
+	}
+	for _, p := range params {
+		p := p
+		t.Run(p.name, func(t *testing.T) {
+			for seed := 0; seed < perCase; seed++ {
+				g := NewWaterBottleGameWithSeed(p.bottles, p.cap, p.colors, p.empty, p.jars, p.jarCap, false, int64(seed))
+				exact, okExact := g.solveBFS(diffTestBudget)
+				if !okExact {
+					t.Fatalf("种子%d: 反向生成的谜题精确求解器找不到解", seed)
+				}
+				if !applySolution(t, g, exact) {
+					t.Fatalf("种子%d: 精确解重放后没有胜利", seed)
+				}
+				heur, okHeur := g.solveBestFirst(diffTestBudget)
+				if !okHeur {
+					t.Fatalf("种子%d: 启发式求解器认为不可解, 与精确求解器矛盾", seed)
+				}
+				if !applySolution(t, g, heur) {
+					t.Fatalf("种子%d: 启发式解重放后没有胜利", seed)
+				}
+				if len(heur) < len(exact) {
+					t.Errorf("种子%d: 启发式解(%d步)比最短解(%d步)还短, BFS不是最短解", seed, len(heur), len(exact))
+				}
+				tt, okTT := g.solveTransposition(diffTestBudget)
+				if !okTT {
+					t.Fatalf("种子%d: 置换表求解器认为不可解, 与精确求解器矛盾", seed)
+				}
+				if !applySolution(t, g, tt) {
+					t.Fatalf("种子%d: 置换表解重放后没有胜利", seed)
+				}
+				if len(tt) < len(exact) {
+					t.Errorf("种子%d: 置换表解(%d步)比最短解(%d步)还短, BFS不是最短解", seed, len(tt), len(exact))
+				}
+			}
+		})
+	}
+}
+
+// TestBagModePuzzlesSolvable 袋子模式走的是另一条生成路径, 单独差分验证.
+// 置换表求解器也在这里过一遍: 袋子收集的增量哈希维护只有这条路径能覆盖.
+func TestBagModePuzzlesSolvable(t *testing.T) {
+	perCase := 200
+	if testing.Short() {
+		perCase = 30
+	}
+	for seed := 0; seed < perCase; seed++ {
+		g := NewWaterBottleGameWithSeed(5, 4, 3, 2, 0, 0, true, int64(seed))
+		exact, ok := g.solveBFS(diffTestBudget)
+		if !ok {
+			t.Fatalf("种子%d: 袋子模式谜题精确求解器找不到解", seed)
+		}
+		if !applySolution(t, g, exact) {
+			t.Fatalf("种子%d: 袋子模式精确解重放后没有胜利", seed)
+		}
+		tt, okTT := g.solveTransposition(diffTestBudget)
+		if !okTT {
+			t.Fatalf("种子%d: 袋子模式下置换表求解器认为不可解", seed)
+		}
+		if !applySolution(t, g, tt) {
+			t.Fatalf("种子%d: 袋子模式置换表解重放后没有胜利", seed)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package game
+
+import "time"
+
+// GameStats 单局过程统计. 计数由引擎在对应操作里累加,
+// 模拟(求解器/可行性检查)过程中的操作不计入.
+type GameStats struct {
+	Moves     int           `json:"moves"`
+	Undos     int           `json:"undos"`
+	Hints     int           `json:"hints"`
+	Restarts  int           `json:"restarts"`
+	Completed int           `json:"completed"` // 完成(装满单色)的瓶数
+	Score     int           `json:"score"`     // 累计分数, 见score.go
+	Elapsed   time.Duration `json:"elapsed"`
+}
+
+// GetStats 返回本局到目前为止的统计快照
+func (g *WaterBottleGame) GetStats() GameStats {
+	s := g.stats
+	s.Moves = g.MoveCount
+	s.Score = g.score
+	s.Elapsed = time.Since(g.startedAt)
+	return s
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"fmt"
+	"io"
+)
+
+// SVG导出: 把当前局面画成带标注的矢量图, 用于分享谜题或嵌入
+// 文档/网页. 谜题包的缩略图(internal/pack)是它的迷你版; 这里画
+// 完整尺寸并标出瓶号, 罐子和袋子. 位图(PNG)导出需要额外的栅格化
+// 依赖, 先不做, 需要时用rsvg/浏览器转换即可.
+
+// SVG画布的尺寸参数(像素)
+const (
+	svgBottleW = 36 // 每个容器的宽度
+	svgLayerH  = 22 // 每层水的高度
+	svgGap     = 14 // 容器之间的间距
+	svgMargin  = 20 // 画布边距
+	svgLabelH  = 18 // 底部标注行的高度
+	svgBagH    = 30 // 袋子信息行的高度
+)
+
+// svgColor 颜色编号到十六进制色值, 复用ANSI渲染的调色板
+func svgColor(c int) string {
+	if c <= 0 || c >= len(colorRGB) {
+		return "#616161"
+	}
+	rgb := colorRGB[c]
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+// ExportSVG 把当前局面写成SVG图. 瓶子和罐子按编号标注, 万能格画成
+// 红蓝对分, 隐藏层画灰色问号, 袋子模式时在下方标出袋子需要的颜色.
+func (g *WaterBottleGame) ExportSVG(w io.Writer) error {
+	maxCap := 0
+	for _, b := range g.Bottles {
+		if b.Capacity > maxCap {
+			maxCap = b.Capacity
+		}
+	}
+	n := len(g.Bottles)
+	width := svgMargin*2 + n*svgBottleW + (n-1)*svgGap
+	height := svgMargin*2 + maxCap*svgLayerH + svgLabelH
+	if len(g.Bags) > 0 {
+		height += svgBagH
+	}
+
+	var err error
+	pr := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format+"\n", args...)
+		}
+	}
+	pr(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	pr(`<rect width="%d" height="%d" fill="#fafafa"/>`, width, height)
+	baseline := svgMargin + maxCap*svgLayerH
+	for i, b := range g.Bottles {
+		x := svgMargin + i*(svgBottleW+svgGap)
+		top := baseline - b.Capacity*svgLayerH
+		stroke := "#555"
+		if b.IsJar {
+			stroke = "#8d6e63" // 罐子用棕色描边区分
+		}
+		pr(`<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s" stroke-width="2" rx="4"/>`,
+			x, top, svgBottleW, b.Capacity*svgLayerH, stroke)
+		for j, c := range b.Water {
+			y := baseline - (j+1)*svgLayerH
+			switch {
+			case !g.isRevealed(i, j):
+				pr(`<rect x="%d" y="%d" width="%d" height="%d" fill="#bdbdbd"/>`, x+2, y+2, svgBottleW-4, svgLayerH-4)
+				pr(`<text x="%d" y="%d" font-size="12" text-anchor="middle" fill="#555">?</text>`, x+svgBottleW/2, y+svgLayerH/2+4)
+			case c == WildcardColor:
+				pr(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, x+2, y+2, (svgBottleW-4)/2, svgLayerH-4, svgColor(1))
+				pr(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, x+2+(svgBottleW-4)/2, y+2, (svgBottleW-4)/2, svgLayerH-4, svgColor(2))
+			default:
+				pr(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, x+2, y+2, svgBottleW-4, svgLayerH-4, svgColor(c))
+			}
+		}
+		label := fmt.Sprintf("%d", i)
+		if b.Collected {
+			label += "✔"
+		}
+		pr(`<text x="%d" y="%d" font-size="12" text-anchor="middle" fill="#333">%s</text>`,
+			x+svgBottleW/2, baseline+svgLabelH-4, label)
+	}
+	for i, bag := range g.Bags {
+		y := baseline + svgLabelH + svgBagH/2
+		x := svgMargin + i*120
+		if bag.Color == 0 {
+			pr(`<text x="%d" y="%d" font-size="13" fill="#333">袋子%d: 完成(%d瓶)</text>`, x, y, i, bag.Collected)
+			continue
+		}
+		pr(`<rect x="%d" y="%d" width="14" height="14" fill="%s" stroke="#555"/>`, x, y-11, svgColor(bag.Color))
+		pr(`<text x="%d" y="%d" font-size="13" fill="#333">袋子%d: 已收%d瓶</text>`, x+20, y, i, bag.Collected)
+	}
+	pr(`</svg>`)
+	return err
+}
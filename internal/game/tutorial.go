@@ -0,0 +1,123 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 教程系统: 用脚本化的小关卡带新手过一遍规则. 每一步有讲解文字和
+// 高亮走法, 默认只允许走高亮的那步, 走完自动进入下一步; 全部步骤
+// 走完后恢复成普通对局.
+
+// TutorialStep 教程的一步
+type TutorialStep struct {
+	Message string // 走这步之前展示的讲解文字
+	Move    Move   // 高亮走法; Free为false时本步只允许走它
+	Free    bool   // 本步不限制走法, 让玩家自由操作一次
+}
+
+// ErrTutorialMove 教程模式下走了高亮走法之外的操作
+var ErrTutorialMove = errors.New("教程模式下只能走高亮的那步")
+
+// TutorialMessageFunc 教程讲解的回调, 进入每一步时被调用一次
+type TutorialMessageFunc func(step int, msg string)
+
+// SetTutorialMessageFunc 注册教程讲解回调, 传nil退回日志输出
+func (g *WaterBottleGame) SetTutorialMessageFunc(fn TutorialMessageFunc) {
+	g.tutorialMsg = fn
+}
+
+// StartTutorial 进入教程模式并宣讲第一步
+func (g *WaterBottleGame) StartTutorial(steps []TutorialStep) {
+	g.tutorial = append([]TutorialStep(nil), steps...)
+	g.tutorialIdx = 0
+	g.announceTutorialStep()
+}
+
+// InTutorial 是否还有教程步骤没走完
+func (g *WaterBottleGame) InTutorial() bool {
+	return g.tutorialIdx < len(g.tutorial)
+}
+
+// CurrentTutorialStep 当前教程步骤; 教程结束后第二个返回值为false
+func (g *WaterBottleGame) CurrentTutorialStep() (TutorialStep, bool) {
+	if !g.InTutorial() {
+		return TutorialStep{}, false
+	}
+	return g.tutorial[g.tutorialIdx], true
+}
+
+// tutorialCheck 教程模式的走子限制. 模拟(求解器/提示)不受限制,
+// 否则教程关里求解器会寸步难行.
+func (g *WaterBottleGame) tutorialCheck(from, to int) error {
+	if !g.InTutorial() || g.suppressEvents {
+		return nil
+	}
+	step := g.tutorial[g.tutorialIdx]
+	if step.Free || (step.Move.From == from && step.Move.To == to) {
+		return nil
+	}
+	return fmt.Errorf("%w: 本步请走 %s -> %s", ErrTutorialMove,
+		g.ContainerName(step.Move.From), g.ContainerName(step.Move.To))
+}
+
+// advanceTutorial 玩家走完一步后推进教程
+func (g *WaterBottleGame) advanceTutorial() {
+	if !g.InTutorial() {
+		return
+	}
+	g.tutorialIdx++
+	if g.InTutorial() {
+		g.announceTutorialStep()
+	} else {
+		g.logger.Logf("🎓 教程完成!")
+	}
+}
+
+// announceTutorialStep 把当前步骤的讲解交给回调或日志
+func (g *WaterBottleGame) announceTutorialStep() {
+	step := g.tutorial[g.tutorialIdx]
+	if g.tutorialMsg != nil {
+		g.tutorialMsg(g.tutorialIdx+1, step.Message)
+		return
+	}
+	g.logger.Logf("🎓 第%d步: %s", g.tutorialIdx+1, step.Message)
+}
+
+// NewJarTutorial 罐子教学关: 三瓶加一个大容量罐子, 演示罐子能装下
+// 超过普通瓶容量的水
+func NewJarTutorial() (*WaterBottleGame, error) {
+	g, err := NewWaterBottleGameFromState(3, 4, 2, 1, 1, 6, false, 1, [][]int{
+		{1, 1, 2, 2},
+		{2, 2, 1, 1},
+		{},
+		{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.StartTutorial([]TutorialStep{
+		{Message: "罐子比普通瓶子能装更多水. 先把瓶子0顶上的两层水倒进罐子", Move: Move{From: 0, To: 3}},
+		{Message: "把瓶子1顶上的同色水倒到瓶子0上, 凑齐一整瓶", Move: Move{From: 1, To: 0}},
+		{Message: "最后把剩下的水也倒进罐子, 罐子不满也算理好", Move: Move{From: 1, To: 3}},
+	})
+	return g, nil
+}
+
+// NewBagTutorial 袋子教学关: 凑齐袋子指定颜色的整瓶就会被收集,
+// 收集后袋子换下一种颜色
+func NewBagTutorial() (*WaterBottleGame, error) {
+	g, err := NewWaterBottleGameFromState(3, 4, 2, 1, 0, 0, true, 1, [][]int{
+		{1, 1, 1},
+		{2, 2, 2, 1},
+		{2},
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.StartTutorial([]TutorialStep{
+		{Message: "袋子上标着它需要的颜色, 凑齐一整瓶就会被收走. 先把瓶子1顶上的水倒到瓶子0", Move: Move{From: 1, To: 0}},
+		{Message: "再把瓶子2的水倒过去凑齐另一种颜色, 袋子会按自己的顺序逐瓶收走", Move: Move{From: 2, To: 1}},
+	})
+	return g, nil
+}
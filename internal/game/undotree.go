@@ -0,0 +1,66 @@
+package game
+
+import "fmt"
+
+// 分支探索: 线性撤销(Undo)之外的"什么if"玩法支撑. 玩家在任意局面打
+// 一个检查点, 之后可以随时跳回去换一条线走, 原来的线也还留在各自的
+// 检查点上, 整体构成一棵以检查点为节点的撤销树. 求解演示UI用它在
+// 几条候选线之间来回切换.
+
+// undoBranch 撤销树的一个节点: 一个带编号的完整状态快照
+type undoBranch struct {
+	id     int
+	parent int // 打点时所在的检查点编号, 0表示根(开局线)
+	snap   *gameSnapshot
+}
+
+// CheckpointInfo 一个检查点的展示信息
+type CheckpointInfo struct {
+	ID        int  // 检查点编号, 从1开始
+	Parent    int  // 父检查点编号, 0表示从开局线上打出
+	MoveCount int  // 打点时的步数
+	Current   bool // 当前局面是否在这个检查点的线上
+}
+
+// Checkpoint 在当前局面打一个检查点, 返回编号.
+// 之后可以用JumpTo随时回到这个局面.
+func (g *WaterBottleGame) Checkpoint() int {
+	b := &undoBranch{
+		id:     len(g.checkpoints) + 1,
+		parent: g.curCheckpoint,
+		snap:   g.copyGameState(),
+	}
+	g.checkpoints = append(g.checkpoints, b)
+	g.curCheckpoint = b.id
+	g.logger.Logf("📌 检查点%d已记录 (第%d步)", b.id, g.MoveCount)
+	return b.id
+}
+
+// JumpTo 跳转到指定检查点的局面. 跳转不合并线性撤销历史:
+// 跳转后Undo只能退回两条线的公共前缀, 各条线的局面靠检查点保留.
+func (g *WaterBottleGame) JumpTo(checkpointID int) error {
+	if checkpointID < 1 || checkpointID > len(g.checkpoints) {
+		return fmt.Errorf("没有检查点%d", checkpointID)
+	}
+	b := g.checkpoints[checkpointID-1]
+	g.restoreGameState(b.snap)
+	g.curCheckpoint = b.id
+	g.revealCurrentTops()
+	g.logger.Logf("📌 跳转到检查点%d (第%d步)", b.id, g.MoveCount)
+	return nil
+}
+
+// Checkpoints 列出所有检查点, 按打点顺序排列.
+// 通过Parent可以还原出整棵撤销树的形状.
+func (g *WaterBottleGame) Checkpoints() []CheckpointInfo {
+	var out []CheckpointInfo
+	for _, b := range g.checkpoints {
+		out = append(out, CheckpointInfo{
+			ID:        b.id,
+			Parent:    b.parent,
+			MoveCount: b.snap.moveCount,
+			Current:   b.id == g.curCheckpoint,
+		})
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package game
+
+import "fmt"
+
+// 参数的严格校验: 提前把建不出合理谜题的参数挡下来, 并给出最近的
+// 可用参数组合. 演示程序的提问环节和关卡加载器都先过这里,
+// 不让坏参数走到生成环节才莫名其妙地失败.
+
+// MaxColors 引擎支持的最大颜色数(受配色表限制)
+var MaxColors = len(colorNames) - 1
+
+// ParamIssue 一条参数问题
+type ParamIssue struct {
+	Field   string `json:"field"`   // 出问题的参数名
+	Message string `json:"message"` // 中文说明
+}
+
+// ParamSet 一组建议的替代参数, Note解释改了什么
+type ParamSet struct {
+	BottleCount  int    `json:"bottleCount"`
+	Capacity     int    `json:"capacity"`
+	ColorCount   int    `json:"colorCount"`
+	EmptyBottles int    `json:"emptyBottles"`
+	JarCount     int    `json:"jarCount"`
+	JarCapacity  int    `json:"jarCapacity"`
+	Note         string `json:"note"`
+}
+
+// ParamValidation 校验结果: OK为false时Issues至少一条,
+// Suggestions给出离原始输入最近的可用参数组合(可能多种改法).
+type ParamValidation struct {
+	OK          bool         `json:"ok"`
+	Issues      []ParamIssue `json:"issues,omitempty"`
+	Suggestions []ParamSet   `json:"suggestions,omitempty"`
+}
+
+// ValidateParams 校验一组建局参数. 返回结构化的问题列表和建议,
+// 调用方可以把Message直接展示给玩家, 或者采纳某条Suggestion重试.
+func ValidateParams(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int) ParamValidation {
+	v := ParamValidation{OK: true}
+	issue := func(field, format string, args ...interface{}) {
+		v.OK = false
+		v.Issues = append(v.Issues, ParamIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	suggest := func(note string, s ParamSet) {
+		s.Note = note
+		v.Suggestions = append(v.Suggestions, s)
+	}
+	base := ParamSet{BottleCount: bottleCount, Capacity: capacity, ColorCount: colorCount,
+		EmptyBottles: emptyBottles, JarCount: jarCount, JarCapacity: jarCapacity}
+
+	if capacity < 2 {
+		issue("capacity", "瓶子容量%d太小, 至少要2层才有排序可言", capacity)
+		s := base
+		s.Capacity = 4
+		suggest("把容量改成常规的4", s)
+	}
+	if capacity > MaxCapacity {
+		issue("capacity", "瓶子容量%d超过引擎上限%d", capacity, MaxCapacity)
+		s := base
+		s.Capacity = MaxCapacity
+		suggest(fmt.Sprintf("把容量降到%d", MaxCapacity), s)
+	}
+	if colorCount < 1 {
+		issue("colorCount", "颜色数量必须至少1种")
+		s := base
+		s.ColorCount = 2
+		suggest("用2种颜色起步", s)
+	}
+	if colorCount > MaxColors {
+		issue("colorCount", "颜色数量%d超过配色表上限%d", colorCount, MaxColors)
+		s := base
+		s.ColorCount = MaxColors
+		suggest(fmt.Sprintf("把颜色减到%d", MaxColors), s)
+	}
+	if emptyBottles < 1 {
+		issue("emptyBottles", "至少要1个空瓶才有周转空间")
+		s := base
+		s.EmptyBottles = 2
+		if s.BottleCount < s.ColorCount+2 {
+			s.BottleCount = s.ColorCount + 2
+		}
+		suggest("加到2个空瓶", s)
+	}
+	if bottleCount < colorCount+emptyBottles {
+		issue("bottleCount", "%d个瓶子装不下%d种颜色外加%d个空瓶",
+			bottleCount, colorCount, emptyBottles)
+		if fewer := bottleCount - emptyBottles; fewer >= 2 {
+			s := base
+			s.ColorCount = fewer
+			suggest(fmt.Sprintf("把颜色减到%d", fewer), s)
+		}
+		s := base
+		s.BottleCount = colorCount + emptyBottles
+		suggest(fmt.Sprintf("把瓶子加到%d", colorCount+emptyBottles), s)
+	}
+	if jarCount < 0 {
+		issue("jarCount", "罐子数量不能是负数")
+		s := base
+		s.JarCount, s.JarCapacity = 0, 0
+		suggest("不用罐子", s)
+	}
+	if jarCount > 0 && jarCapacity < 2 {
+		issue("jarCapacity", "罐子容量%d太小, 至少要2层", jarCapacity)
+		s := base
+		s.JarCapacity = capacity + 2
+		suggest(fmt.Sprintf("罐子容量用%d(比瓶子多2层)", capacity+2), s)
+	}
+	if jarCount > 0 && jarCapacity > MaxCapacity {
+		issue("jarCapacity", "罐子容量%d超过引擎上限%d", jarCapacity, MaxCapacity)
+		s := base
+		s.JarCapacity = MaxCapacity
+		suggest(fmt.Sprintf("把罐子容量降到%d", MaxCapacity), s)
+	}
+	return v
+}
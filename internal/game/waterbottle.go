@@ -0,0 +1,1328 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// 颜色编号从1开始, 0表示没有水
+var colorNames = []string{"", "红", "蓝", "绿", "黄", "紫", "橙", "青", "粉", "棕", "灰"}
+var colorEmojis = []string{"", "🔴", "🔵", "🟢", "🟡", "🟣", "🟠", "🟦", "🟥", "🟫", "⚪"}
+
+// getColorName 返回颜色在当前语言下的名称, 见i18n.go
+func getColorName(color int) string {
+	if color == WildcardColor {
+		return tr("color.wild")
+	}
+	names := bundles[currentLang].colorNames
+	if color <= 0 || color >= len(names) {
+		return fmt.Sprintf(tr("color.unknown"), color)
+	}
+	return names[color]
+}
+
+// getColorEmoji 返回颜色对应的表情符号
+func getColorEmoji(color int) string {
+	if color == WildcardColor {
+		return "🌈"
+	}
+	if color <= 0 || color >= len(colorEmojis) {
+		return "❓"
+	}
+	return colorEmojis[color]
+}
+
+// Bottle 表示一个瓶子(或罐子), 水从底部向顶部堆叠
+type Bottle struct {
+	Water     []int   // 水层颜色, 下标0是瓶底
+	Capacity  int     // 容量
+	IsJar     bool    // 是否是罐子
+	Collected bool    // 是否已被袋子收集
+	Kind      JarKind // 罐子的行为变体, 见jarkind.go; 普通瓶子忽略
+}
+
+// IsEmpty 瓶子是否为空
+func (b *Bottle) IsEmpty() bool {
+	return len(b.Water) == 0
+}
+
+// IsFull 瓶子是否已满
+func (b *Bottle) IsFull() bool {
+	return len(b.Water) >= b.Capacity
+}
+
+// TopColor 瓶口的颜色, 空瓶返回0
+func (b *Bottle) TopColor() int {
+	if b.IsEmpty() {
+		return 0
+	}
+	return b.Water[len(b.Water)-1]
+}
+
+// TopRunLength 瓶口连续同色水的层数. 万能格并入所在的段,
+// 段的具体颜色由从瓶口往下第一个非万能格决定.
+func (b *Bottle) TopRunLength() int {
+	if b.IsEmpty() {
+		return 0
+	}
+	c := b.TopColor()
+	n := 0
+	for i := len(b.Water) - 1; i >= 0; i-- {
+		w := b.Water[i]
+		if !colorsCompatible(w, c) {
+			break
+		}
+		if c == WildcardColor {
+			c = w
+		}
+		n++
+	}
+	return n
+}
+
+// isSingleColor 瓶中的水是否只有一种颜色(万能格算作任意颜色)
+func isSingleColor(b *Bottle) bool {
+	c := WildcardColor
+	for _, w := range b.Water {
+		if !colorsCompatible(w, c) {
+			return false
+		}
+		if c == WildcardColor {
+			c = w
+		}
+	}
+	return true
+}
+
+// Bag 收集袋, 收集装满指定颜色的瓶子
+type Bag struct {
+	Color     int // 当前需要收集的颜色, 0表示已收完
+	Collected int // 已收集的瓶子数量
+}
+
+// Move 一次倒水动作
+type Move struct {
+	From   int // 源瓶下标
+	To     int // 目标瓶下标
+	Amount int // 倒的水层数
+}
+
+// gameSnapshot 游戏状态快照, 用于撤销和模拟.
+// 瓶子和袋子存压缩值表示(见packed.go), 整个快照只有几次切片分配.
+type gameSnapshot struct {
+	bottles    []packedBottle
+	bags       []Bag
+	collected  map[int]bool
+	moveCount  int
+	historyLen int
+	moveLogLen int
+	coins      int
+	bombFuse   int
+	bagQueue   []int
+	hash       uint64
+}
+
+// WaterBottleGame 倒水排序游戏
+type WaterBottleGame struct {
+	Bottles      []*Bottle // 所有容器, 普通瓶子在前, 罐子在后
+	BottleCount  int       // 普通瓶子数量(含空瓶)
+	Capacity     int       // 普通瓶子容量
+	ColorCount   int       // 颜色数量
+	EmptyBottles int       // 初始空瓶数量
+	JarCount     int       // 罐子数量
+	JarCapacity  int       // 构造时罐子的统一容量; 各罐子的实际容量在Bottle.Capacity上
+	UseBags      bool      // 是否开启袋子模式
+	StrictMode   bool      // 严格模式: 禁止倒出已完成(装满单色)的瓶子
+	Rules        RuleSet   // 规则变体, 默认经典倒水
+	Mystery      bool      // 隐藏层模式, 见mystery.go
+	Bags         []*Bag    // 袋子
+	MoveCount    int       // 已走步数
+
+	collectedColors  map[int]bool    // 袋子模式下已收集完成的颜色
+	bagQueue         []int           // 袋子的颜色队列, 按出队顺序排列
+	history          []*gameSnapshot // 撤销历史
+	logger           Logger          // 引擎消息输出, 默认静默
+	safeCheckpoint   *gameSnapshot   // 最近一次被求解器确认可解的局面
+	handlers         []EventHandler  // 事件订阅者
+	suppressEvents   bool            // 模拟过程中屏蔽事件
+	moveLog          []MoveLogEntry  // 真实走子日志(不含模拟)
+	rng              *rand.Rand      // 本局游戏专用随机源
+	seed             int64           // 创建时使用的种子
+	audit            RandAuditLog    // 生成阶段的随机决策审计日志
+	debug            bool            // 调试模式, 解锁作弊接口
+	stats            GameStats       // 本局过程统计(撤销/提示等计数)
+	startedAt        time.Time       // 本局开始时间
+	coins            int             // 金币余额, 见economy.go
+	revealed         [][]bool        // 隐藏层模式下每层是否已被揭示
+	par              int             // 标准步数缓存, 见par.go
+	checkpoints      []*undoBranch   // 撤销树的检查点, 见undotree.go
+	curCheckpoint    int             // 当前所在的检查点编号, 0表示开局线
+	sandboxBase      *gameSnapshot   // 沙盒的反悔点, nil表示不在沙盒里
+	sandboxStats     GameStats       // 进沙盒时的过程统计, 放弃时还原
+	hash             uint64          // 局面的增量Zobrist哈希, 见zobrist.go
+	ctx              context.Context // 长时操作的取消上下文, 见context.go
+	progress         ProgressFunc    // 长时操作的进度回调, 见progress.go
+	moveLimit        int             // 步数上限, 0表示不限, 见challenge.go
+	deadline         time.Time       // 倒计时截止时刻, 零值表示不限
+	timeLossNotified bool            // 超时事件只发一次
+	bombFuse         int             // 炸弹引信剩余步数, 见bomb.go
+	bombLossNotified bool            // 爆炸事件只发一次
+	endlessEvery     int             // 生存模式每几步上新瓶, 0表示关闭, 见endless.go
+	endlessCap       int             // 生存模式的容器总数上限
+	objectives       []Objective     // 关卡目标清单, 见objective.go
+	completionOrder  []int           // 瓶子完成的颜色顺序(真实走子)
+	winCond          WinCondition    // 胜利条件插件, nil用默认, 见wincondition.go
+	tutorial         []TutorialStep  // 教程步骤脚本, 见tutorial.go
+	tutorialIdx      int             // 教程进行到第几步
+	tutorialMsg      TutorialMessageFunc
+	displayStyle     DisplayStyle // 水层的默认显示样式, 见displaystyle.go
+	scoring          ScoringConfig
+	score            int            // 累计分数, 见score.go
+	combo            int            // 连击数: 连续有完成的回合数
+	turnCompletions  int            // 本回合完成的瓶数, 结算后清零
+	economyMode      bool           // 经济模式: 道具收费, 通关发奖, 见economy.go
+	winRewardPaid    bool           // 通关奖励只发一次
+	powerUps         []*PowerUpSlot // 道具栏, 见powerup.go
+	genRetries       int            // 生成的重试上限, 0取默认, 见generation.go
+	genReport        GenerationReport
+	diffBands        *DifficultyBands // 校准出的难度区间, nil用内置值, 见calibrate.go
+	redoStack        []*gameSnapshot  // 被撤销的状态, 供重做, 见restart.go
+	initialSnap      *gameSnapshot    // 初始布局快照, 供重开, 见restart.go
+}
+
+// NewWaterBottleGame 创建一局新游戏, 使用当前时间作为随机种子
+func NewWaterBottleGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool) *WaterBottleGame {
+	return NewWaterBottleGameWithSeed(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags, time.Now().UnixNano())
+}
+
+// NewWaterBottleGameWithSeed 使用指定种子创建游戏, 相同参数和种子会生成相同的谜题
+func NewWaterBottleGameWithSeed(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool, seed int64) *WaterBottleGame {
+	g := newEmptyGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags, seed)
+	g.generateInitialState()
+	if useBags {
+		for _, bag := range g.Bags {
+			if bag.Color == 0 {
+				bag.Color = g.nextBagColor()
+			}
+		}
+	}
+	return g
+}
+
+// NewWaterBottleGameLazy 创建不做初始生成的对局: 局面直接摆成已解好
+// 状态, 立即可以渲染. 配合GenerateInBackground在大盘上做冷启动优化,
+// 真正的谜题生成完后用SwapGenerated换入.
+func NewWaterBottleGameLazy(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool) *WaterBottleGame {
+	g := newEmptyGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags, time.Now().UnixNano())
+	g.createSolvedState()
+	return g
+}
+
+// newEmptyGame 构造空对局: 只建容器, 袋子和随机源, 不做初始生成
+func newEmptyGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity int, useBags bool, seed int64) *WaterBottleGame {
+	g := &WaterBottleGame{
+		BottleCount:     bottleCount,
+		Capacity:        capacity,
+		ColorCount:      colorCount,
+		EmptyBottles:    emptyBottles,
+		JarCount:        jarCount,
+		JarCapacity:     jarCapacity,
+		UseBags:         useBags,
+		collectedColors: make(map[int]bool),
+		rng:             rand.New(rand.NewSource(seed)),
+		logger:          SilentLogger,
+		seed:            seed,
+		startedAt:       time.Now(),
+		scoring:         DefaultScoringConfig(),
+	}
+	for i := 0; i < bottleCount; i++ {
+		g.Bottles = append(g.Bottles, &Bottle{Capacity: capacity})
+	}
+	for i := 0; i < jarCount; i++ {
+		g.Bottles = append(g.Bottles, &Bottle{Capacity: jarCapacity, IsJar: true})
+	}
+	if useBags {
+		g.Bags = append(g.Bags, &Bag{})
+		g.initBagQueue()
+	}
+	return g
+}
+
+// SetStrictMode 开关严格模式(禁止倒出已完成的瓶子).
+// 求解器和提示都通过Pour走子, 会自动遵守这一规则.
+func (g *WaterBottleGame) SetStrictMode(on bool) {
+	g.StrictMode = on
+}
+
+// Seed 返回本局游戏使用的随机种子
+func (g *WaterBottleGame) Seed() int64 {
+	return g.seed
+}
+
+// generateInitialState 生成初始局面
+func (g *WaterBottleGame) generateInitialState() {
+	steps := g.BottleCount * g.Capacity * 2
+	if g.UseBags {
+		g.generateByReverseForBags(steps)
+		return
+	}
+	g.generateByReverseWithSteps(steps)
+}
+
+// GenerateByReverse 按指定步数重新反向生成谜题, steps<=0时用默认步数.
+// 命令行的 --gen reverse --steps N 用它重新生成.
+func (g *WaterBottleGame) GenerateByReverse(steps int) {
+	if steps <= 0 {
+		steps = g.BottleCount * g.Capacity * 2
+	}
+	g.par = 0
+	if g.UseBags {
+		g.resetBagState()
+		g.generateByReverseForBags(steps)
+		for _, bag := range g.Bags {
+			if bag.Color == 0 {
+				bag.Color = g.nextBagColor()
+			}
+		}
+		return
+	}
+	g.generateByReverseWithSteps(steps)
+}
+
+// generateRandomState 把所有颜色的水随机分配到非空瓶中
+func (g *WaterBottleGame) generateRandomState() {
+	var pool []int
+	for c := 1; c <= g.ColorCount; c++ {
+		for i := 0; i < g.Capacity; i++ {
+			pool = append(pool, c)
+		}
+	}
+	g.auditShuffle("随机生成:洗牌", len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+	// 清空所有容器: 失败的反向生成尝试可能在任何瓶子里留下水
+	for _, b := range g.Bottles {
+		b.Water = b.Water[:0]
+	}
+	filled := g.BottleCount - g.EmptyBottles
+	for _, c := range pool {
+		// 随机选一个还有空间的瓶子
+		var candidates []int
+		for i := 0; i < filled; i++ {
+			if !g.Bottles[i].IsFull() {
+				candidates = append(candidates, i)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		idx := candidates[g.auditIntn("随机生成:选瓶", len(candidates))]
+		g.Bottles[idx].Water = append(g.Bottles[idx].Water, c)
+	}
+}
+
+// GenerateRandomSolvable 反复随机生成并用求解器验证, 直到得到可解的谜题.
+// 随机分布不保证可解, 这里用求解器过滤; 超过maxAttempts次仍未成功时
+// 返回错误, 局面保留最后一次尝试的结果.
+func (g *WaterBottleGame) GenerateRandomSolvable(maxAttempts int) error {
+	g.par = 0
+	start := time.Now()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		g.generateRandomState()
+		if _, ok := g.Solve(); ok {
+			g.recordGeneration(0, 0, attempt, true, start)
+			g.logger.Logf("✅ 随机生成可解谜题 (第%d次尝试)", attempt)
+			return nil
+		}
+		g.logger.Logf("⚠️ 第%d次随机生成不可解, 重试", attempt)
+	}
+	g.recordGeneration(0, 0, maxAttempts, false, start)
+	return fmt.Errorf("随机生成%d次后仍未得到可解的谜题", maxAttempts)
+}
+
+// createSolvedState 创建一个已完成的局面: K个瓶子各装满一种颜色
+func (g *WaterBottleGame) createSolvedState() {
+	for _, b := range g.Bottles {
+		b.Water = b.Water[:0]
+	}
+	// 哪些瓶子装哪种颜色(以及哪些留空)由种子随机决定, 不然反向生成的
+	// 谜题会共享"前几个瓶子总是满的"这种一眼能认出来的痕迹
+	idx := make([]int, g.BottleCount)
+	for i := range idx {
+		idx[i] = i
+	}
+	g.auditShuffle("解态:选瓶", len(idx), func(i, j int) {
+		idx[i], idx[j] = idx[j], idx[i]
+	})
+	for c := 1; c <= g.ColorCount && c-1 < len(idx); c++ {
+		b := g.Bottles[idx[c-1]]
+		for i := 0; i < g.Capacity; i++ {
+			b.Water = append(b.Water, c)
+		}
+	}
+}
+
+// generateByReverseWithSteps 从完成局面反向倒水打乱, 生成必定可解的谜题
+func (g *WaterBottleGame) generateByReverseWithSteps(steps int) {
+	maxAttempts := g.generationRetries()
+	start := time.Now()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if g.cancelled() != nil {
+			return
+		}
+		g.createSolvedState()
+		var forwardMoves []Move
+		done := 0
+		for done < steps {
+			if g.cancelled() != nil {
+				return
+			}
+			m, ok := g.randomReverseMove()
+			if !ok {
+				break
+			}
+			forwardMoves = append([]Move{m}, forwardMoves...)
+			done++
+			g.reportProgress("反向生成进度", done, steps)
+		}
+		if g.validateReverseSteps(forwardMoves) {
+			g.recordGeneration(steps, done, attempt, true, start)
+			g.logger.Logf("✅ 反向生成完成 (第%d次尝试, 共%d步)", attempt, done)
+			return
+		}
+		g.logger.Logf("⚠️ 第%d次反向生成验证失败, 重试", attempt)
+	}
+	g.recordGeneration(steps, 0, maxAttempts, false, start)
+	g.logger.Logf("⚠️ 反向生成多次失败, 退回随机生成")
+	g.generateRandomState()
+}
+
+// generateByReverseForBags 袋子模式的反向生成. 反向打乱和普通模式一样,
+// 但收集语义会在中途移除装满的瓶子, 记录的正向步骤不一定能原样重放,
+// 所以改用真实求解器验证可解性, 失败则重试, 多次失败退回随机生成.
+func (g *WaterBottleGame) generateByReverseForBags(steps int) {
+	maxAttempts := g.generationRetries()
+	start := time.Now()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if g.cancelled() != nil {
+			return
+		}
+		g.generateByReverseWithSteps(steps)
+		for _, bag := range g.Bags {
+			if bag.Color == 0 {
+				bag.Color = g.nextBagColor()
+			}
+		}
+		if _, ok := g.Solve(); ok {
+			// 内层反向生成已经写过一次报告, 这里换成整轮的口径
+			g.recordGeneration(steps, g.genReport.ActualSteps, attempt, true, start)
+			g.logger.Logf("✅ 袋子模式生成通过求解验证 (第%d次尝试)", attempt)
+			return
+		}
+		g.logger.Logf("⚠️ 第%d次袋子模式生成未通过求解验证, 重试", attempt)
+		g.resetBagState()
+	}
+	g.recordGeneration(steps, 0, maxAttempts, false, start)
+	g.logger.Logf("⚠️ 袋子模式反向生成多次失败, 退回随机生成")
+	g.generateRandomState()
+}
+
+// resetBagState 清空袋子和收集记录, 供生成重试使用
+func (g *WaterBottleGame) resetBagState() {
+	for _, bag := range g.Bags {
+		bag.Color = 0
+		bag.Collected = 0
+	}
+	g.collectedColors = make(map[int]bool)
+	for _, b := range g.Bottles {
+		b.Collected = false
+	}
+	g.initBagQueue()
+}
+
+// randomReverseMove 随机执行一步反向倒水, 返回对应的正向走法.
+// 罐子也参与反向操作, 这样JarCount>0的谜题才会真正用到罐子,
+// 对应的正向解法会把罐子里的水倒回瓶子.
+func (g *WaterBottleGame) randomReverseMove() (Move, bool) {
+	type candidate struct {
+		from, to, amount int
+	}
+	var candidates []candidate
+	for from := 0; from < len(g.Bottles); from++ {
+		fb := g.Bottles[from]
+		if fb.IsEmpty() {
+			continue
+		}
+		run := fb.TopRunLength()
+		c := fb.TopColor()
+		for to := 0; to < len(g.Bottles); to++ {
+			if to == from {
+				continue
+			}
+			tb := g.Bottles[to]
+			space := tb.Capacity - len(tb.Water)
+			if space <= 0 {
+				continue
+			}
+			maxAmount := run
+			if maxAmount > space {
+				maxAmount = space
+			}
+			for amount := 1; amount <= maxAmount; amount++ {
+				// 反向移走amount层后, 正向倒水(to->from)必须合法
+				remainTop := 0
+				if len(fb.Water)-amount > 0 {
+					remainTop = fb.Water[len(fb.Water)-amount-1]
+				}
+				if remainTop == 0 || remainTop == c {
+					candidates = append(candidates, candidate{from, to, amount})
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Move{}, false
+	}
+	ch := candidates[g.auditIntn("反向生成:选步", len(candidates))]
+	g.ApplyMove(Move{From: ch.from, To: ch.to, Amount: ch.amount})
+	// 正向走法是把水倒回去
+	return Move{From: ch.to, To: ch.from, Amount: ch.amount}, true
+}
+
+// validateReverseSteps 正向重放反向生成记录的走法, 验证能回到完成局面
+func (g *WaterBottleGame) validateReverseSteps(moves []Move) bool {
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	snap := g.borrowSnapshot()
+	defer func() {
+		g.restoreGameState(snap)
+		releaseSnapshot(snap)
+	}()
+	for i, m := range moves {
+		if _, err := g.Pour(m.From, m.To); err != nil {
+			g.logger.Logf("  验证失败: 第%d步 %d->%d 不合法: %v", i+1, m.From, m.To, err)
+			return false
+		}
+		g.reportProgress("验证进度", i+1, len(moves))
+	}
+	return g.isSortedState()
+}
+
+// validatePour 校验一次倒水是否合法, 返回将要倒的水层数.
+// Pour和GetPourFailureReason都基于它, 校验逻辑只存在这一份.
+func (g *WaterBottleGame) validatePour(from, to int) (int, error) {
+	if from < 0 || from >= len(g.Bottles) || to < 0 || to >= len(g.Bottles) {
+		return 0, ErrInvalidIndex
+	}
+	if from == to {
+		return 0, ErrSameBottle
+	}
+	fb, tb := g.Bottles[from], g.Bottles[to]
+	if fb.Collected || tb.Collected {
+		return 0, ErrCollectedBottle
+	}
+	if fb.IsEmpty() {
+		return 0, ErrSourceEmpty
+	}
+	if isFrozenColor(fb.TopColor()) {
+		return 0, ErrFrozenLayer
+	}
+	if fb.IsJar && fb.Kind == JarReceiveOnly {
+		return 0, ErrJarReceiveOnly
+	}
+	if tb.IsFull() {
+		return 0, ErrTargetFull
+	}
+	if g.StrictMode && fb.IsFull() && isSingleColor(fb) {
+		return 0, ErrStrictModeLocked
+	}
+	c := fb.TopColor()
+	// 炸弹一次只倒一格, 且必须落到顶层同底色的水上(即拆除)
+	if isBombColor(c) {
+		if tb.IsEmpty() || layerBase(tb.TopColor()) != bombBase(c) {
+			return 0, bombPourError
+		}
+		return 1, nil
+	}
+	// 冰冻/炸弹的目标顶层按底色匹配: 同色水可以落上去
+	tt := layerBase(tb.TopColor())
+	// 缓冲罐无视顶层颜色, 什么水都收
+	if !tb.IsEmpty() && !(tb.IsJar && tb.Kind == JarBuffer) && !colorsCompatible(c, tt) {
+		return 0, fmt.Errorf("%w: %s不能倒在%s上", ErrColorMismatch, getColorName(c), getColorName(tt))
+	}
+	amount := fb.TopRunLength()
+	if g.Rules == RuleBallSort {
+		// 球排序: 一次只拿一颗球
+		amount = 1
+	}
+	if space := tb.Capacity - len(tb.Water); amount > space {
+		amount = space
+	}
+	return amount, nil
+}
+
+// Pour 从from瓶往to瓶倒水, 返回实际倒的水层数.
+// 失败时返回类型化错误(ErrSourceEmpty/ErrTargetFull/ErrColorMismatch等).
+func (g *WaterBottleGame) Pour(from, to int) (int, error) {
+	if err := g.tutorialCheck(from, to); err != nil {
+		return 0, err
+	}
+	amount, err := g.validatePour(from, to)
+	if err != nil {
+		return 0, err
+	}
+	g.applyPour(from, to, amount)
+	return amount, nil
+}
+
+// PourAmount 从from往to只倒amount层水. Pour总是搬整段同色水, 这里允许
+// 玩家刻意少倒几层(目标空间紧张时留一手是常见策略). amount必须在1到
+// 本次可倒上限(顶部同色段长度与目标剩余空间的较小值)之间.
+func (g *WaterBottleGame) PourAmount(from, to, amount int) (int, error) {
+	if err := g.tutorialCheck(from, to); err != nil {
+		return 0, err
+	}
+	max, err := g.validatePour(from, to)
+	if err != nil {
+		return 0, err
+	}
+	if amount < 1 || amount > max {
+		return 0, fmt.Errorf("%w: 本次可倒1~%d层, 要求%d层", ErrInvalidAmount, max, amount)
+	}
+	g.applyPour(from, to, amount)
+	return amount, nil
+}
+
+// applyPour 执行一次已通过校验的倒水: 记历史, 搬水, 记日志并触发事件
+func (g *WaterBottleGame) applyPour(from, to, amount int) {
+	g.captureInitialState()
+	g.pushHistory()
+	g.pourOne(from, to, amount)
+	g.MoveCount++
+	g.tickBombFuse()
+	if !g.suppressEvents {
+		g.moveLog = append(g.moveLog, MoveLogEntry{Move: Move{From: from, To: to, Amount: amount}, Time: time.Now()})
+		g.advanceTutorial()
+	}
+	g.finishTurn()
+}
+
+// pourOne 搬一股水并触发倒水/完成事件, 不碰历史和步数.
+// 单手的applyPour和双手的PourBoth都用它做实际的搬运.
+func (g *WaterBottleGame) pourOne(from, to, amount int) {
+	fb, tb := g.Bottles[from], g.Bottles[to]
+	c := fb.TopColor()
+	g.hash -= bottleHash(fb) + bottleHash(tb)
+	// 搬真实水格而不是按颜色重新生成: 万能格倒过去仍然是万能格
+	before := len(tb.Water)
+	tb.Water = append(tb.Water, fb.Water[len(fb.Water)-amount:]...)
+	fb.Water = fb.Water[:len(fb.Water)-amount]
+	g.hash += bottleHash(fb) + bottleHash(tb)
+	g.meltAdjacent(to, before, c)
+	g.defuseLanded(to, before)
+	g.revealRange(to, before, len(tb.Water))
+	g.revealCurrentTops()
+	g.emit(Event{Type: EventPourPerformed, Move: Move{From: from, To: to, Amount: amount}, Bottle: to, Bag: -1, Color: c})
+	if tb.IsFull() && isSingleColor(tb) {
+		done := effectiveColor(tb)
+		g.emit(Event{Type: EventBottleCompleted, Bottle: to, Bag: -1, Color: done})
+		if !g.suppressEvents {
+			g.turnCompletions++
+			g.stats.Completed++
+			g.completionOrder = append(g.completionOrder, done)
+			g.coins += CoinsPerBottleCompleted
+			g.logger.Logf("💰 完成一瓶%s, 奖励%d金币 (余额%d)", getColorName(done), CoinsPerBottleCompleted, g.coins)
+		}
+	}
+}
+
+// finishTurn 一个回合收尾: 袋子收集, 得分结算和胜负判定
+func (g *WaterBottleGame) finishTurn() {
+	if g.UseBags {
+		g.checkBagCollection()
+	}
+	g.settleTurnScore()
+	if !g.suppressEvents {
+		g.maybeSpawnGarbage()
+		if g.IsWon() {
+			g.payWinReward()
+			g.emit(Event{Type: EventWon, Bottle: -1, Bag: -1})
+		} else if g.timedOut() {
+			if !g.timeLossNotified {
+				g.timeLossNotified = true
+				g.emit(Event{Type: EventTimeExpired, Bottle: -1, Bag: -1})
+			}
+		} else if g.bombExploded() {
+			if !g.bombLossNotified {
+				g.bombLossNotified = true
+				g.emit(Event{Type: EventBombExploded, Bottle: -1, Bag: -1})
+			}
+		} else if g.moveLimit > 0 && g.MoveCount == g.moveLimit {
+			g.emit(Event{Type: EventMoveLimitReached, Bottle: -1, Bag: -1})
+		} else if !g.hasLegalMove() {
+			g.emit(Event{Type: EventDeadlock, Bottle: -1, Bag: -1})
+		}
+	}
+}
+
+// PourBoth 双手模式: 两次互不冲突的倒水作为一个回合提交, 原子生效.
+// 两次倒水不能共用任何容器(否则先后顺序会影响结果), 任意一次校验
+// 失败整个回合都不执行. 历史和步数都按一步记, 撤销一次回退两股水.
+func (g *WaterBottleGame) PourBoth(from1, to1, from2, to2 int) (int, int, error) {
+	if from1 == from2 || from1 == to2 || to1 == from2 || to1 == to2 {
+		return 0, 0, fmt.Errorf("%w: 双手倒水不能共用容器", ErrInvalidIndex)
+	}
+	amount1, err := g.validatePour(from1, to1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("左手: %w", err)
+	}
+	amount2, err := g.validatePour(from2, to2)
+	if err != nil {
+		return 0, 0, fmt.Errorf("右手: %w", err)
+	}
+	g.captureInitialState()
+	g.pushHistory()
+	g.pourOne(from1, to1, amount1)
+	g.pourOne(from2, to2, amount2)
+	g.MoveCount++
+	g.tickBombFuse()
+	if !g.suppressEvents {
+		now := time.Now()
+		g.moveLog = append(g.moveLog, MoveLogEntry{
+			Move:   Move{From: from1, To: to1, Amount: amount1},
+			Second: &Move{From: from2, To: to2, Amount: amount2},
+			Time:   now,
+		})
+	}
+	g.finishTurn()
+	return amount1, amount2, nil
+}
+
+// GetPourFailureReason 返回倒水失败的原因(按当前语言), 成功时返回空串.
+// 校验直接来自validatePour, 文案经过i18n.go的目录翻译.
+func (g *WaterBottleGame) GetPourFailureReason(from, to int) string {
+	if _, err := g.validatePour(from, to); err != nil {
+		return g.localizePourError(from, to, err)
+	}
+	return ""
+}
+
+// checkBagCollection 检查是否有装满单色水的瓶子可以被袋子收集
+func (g *WaterBottleGame) checkBagCollection() {
+	// 循环直到没有新的收集发生: 袋子换色后可能立刻轮到已完成的瓶子
+	for again := true; again; {
+		again = false
+		for bi, bag := range g.Bags {
+			if bag.Color == 0 {
+				continue
+			}
+			if g.collectOneBottle(bi, bag) {
+				again = true
+			}
+		}
+	}
+}
+
+// collectOneBottle 尝试让一个袋子收集一瓶, 成功返回true
+func (g *WaterBottleGame) collectOneBottle(bi int, bag *Bag) bool {
+	for i, b := range g.Bottles {
+		if b.Collected || b.IsJar || !b.IsFull() || !isSingleColor(b) {
+			continue
+		}
+		if c := effectiveColor(b); c != bag.Color && c != WildcardColor {
+			continue
+		}
+		g.hash -= bottleHash(b) + bagHash(bi, bag)
+		b.Collected = true
+		b.Water = b.Water[:0]
+		bag.Collected++
+		g.collectedColors[bag.Color] = true
+		g.logger.Logf("🎉 袋子收集了瓶子%d (%s)", i, getColorName(bag.Color))
+		if !g.suppressEvents {
+			g.coins += CoinsPerBagCollected
+			g.logger.Logf("💰 收集奖励%d金币 (余额%d)", CoinsPerBagCollected, g.coins)
+		}
+		g.emit(Event{Type: EventBottleCollectedByBag, Bottle: i, Bag: bi, Color: bag.Color})
+		bag.Color = g.nextBagColor()
+		g.hash += bottleHash(b) + bagHash(bi, bag)
+		g.emit(Event{Type: EventBagColorChanged, Bottle: -1, Bag: bi, Color: bag.Color})
+		if bag.Color != 0 {
+			g.logger.Logf("👜 袋子现在需要: %s", getColorName(bag.Color))
+		}
+		return true
+	}
+	return false
+}
+
+// getAvailableColors 返回还没有被收集的颜色(不含正在袋子上的)
+func (g *WaterBottleGame) getAvailableColors() []int {
+	onBag := make(map[int]bool)
+	for _, bag := range g.Bags {
+		if bag.Color != 0 {
+			onBag[bag.Color] = true
+		}
+	}
+	var colors []int
+	for c := 1; c <= g.ColorCount; c++ {
+		if !g.collectedColors[c] && !onBag[c] {
+			colors = append(colors, c)
+		}
+	}
+	return colors
+}
+
+// BagColorRemaining 统计某个袋子的目标颜色在场上还剩多少格水
+// (包括罐子里的), 以及按瓶子容量还能凑满多少瓶, 供玩家决定先喂哪个袋子.
+func (g *WaterBottleGame) BagColorRemaining(bagIndex int) (units, bottles int) {
+	if bagIndex < 0 || bagIndex >= len(g.Bags) || g.Bags[bagIndex].Color == 0 {
+		return 0, 0
+	}
+	c := g.Bags[bagIndex].Color
+	for _, b := range g.Bottles {
+		if b.Collected {
+			continue
+		}
+		for _, w := range b.Water {
+			if w == c {
+				units++
+			}
+		}
+	}
+	return units, units / g.Capacity
+}
+
+// initBagQueue 构建袋子的颜色队列. 队列是显式且确定的: 按颜色编号
+// 从小到大排列, 袋子永远按队列顺序换色, UI可以据此做"下一个颜色"预告.
+func (g *WaterBottleGame) initBagQueue() {
+	g.bagQueue = g.bagQueue[:0]
+	for c := 1; c <= g.ColorCount; c++ {
+		g.bagQueue = append(g.bagQueue, c)
+	}
+}
+
+// nextBagColor 从颜色队列出队下一个需要收集的颜色, 没有则返回0.
+// 已收集或已在别的袋子上的颜色会被跳过并丢弃.
+func (g *WaterBottleGame) nextBagColor() int {
+	onBag := make(map[int]bool)
+	for _, bag := range g.Bags {
+		if bag.Color != 0 {
+			onBag[bag.Color] = true
+		}
+	}
+	for len(g.bagQueue) > 0 {
+		c := g.bagQueue[0]
+		g.bagQueue = g.bagQueue[1:]
+		if g.collectedColors[c] || onBag[c] {
+			continue
+		}
+		return c
+	}
+	return 0
+}
+
+// PeekNextBagColors 预览颜色队列里接下来最多n个会被分配的颜色,
+// 不改变队列. 玩家和UI可以提前看到"下一个袋子要什么"来规划倒水顺序.
+func (g *WaterBottleGame) PeekNextBagColors(n int) []int {
+	onBag := make(map[int]bool)
+	for _, bag := range g.Bags {
+		if bag.Color != 0 {
+			onBag[bag.Color] = true
+		}
+	}
+	var out []int
+	for _, c := range g.bagQueue {
+		if len(out) >= n {
+			break
+		}
+		if g.collectedColors[c] || onBag[c] {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// IsWon 判断是否获胜. 设置过胜利条件插件时按插件判定,
+// 否则按模式取默认条件(袋子收集/经典整理), 见wincondition.go.
+func (g *WaterBottleGame) IsWon() bool {
+	if g.winCond != nil {
+		return g.winCond.Satisfied(g)
+	}
+	if g.UseBags {
+		return WinAllCollected{}.Satisfied(g)
+	}
+	return WinAllSorted{}.Satisfied(g)
+}
+
+// isSortedState 是否已整理完毕(不考虑袋子收集):
+// 每个容器为空或单色, 且每种颜色只占一个容器
+func (g *WaterBottleGame) isSortedState() bool {
+	seen := make(map[int]bool)
+	for _, b := range g.Bottles {
+		// 封印罐必须倒空才能判胜
+		if b.IsJar && b.Kind == JarSealed && !b.IsEmpty() {
+			return false
+		}
+		if b.Collected || b.IsEmpty() {
+			continue
+		}
+		if !isSingleColor(b) {
+			return false
+		}
+		// 全万能格的瓶子算作任意一种颜色, 不占用seen
+		if c := effectiveColor(b); c != WildcardColor {
+			if seen[c] {
+				return false
+			}
+			seen[c] = true
+		}
+	}
+	return true
+}
+
+// CanPour 无副作用地预判一次倒水: 返回是否可行, 可倒的水层数,
+// 以及不可行时的中文原因. 不复制也不改动任何状态, 适合每帧/每步
+// 高频调用(UI高亮可落点, 死局检测等).
+func (g *WaterBottleGame) CanPour(from, to int) (bool, int, string) {
+	amount, err := g.validatePour(from, to)
+	if err != nil {
+		return false, 0, err.Error()
+	}
+	return true, amount, ""
+}
+
+// AutoPour 只选起点的"一键倒水": 为from自动挑一个目标并执行, 对应
+// 手游里点一下瓶子就倒的操作. 选择规则按优先级: 能搬走的水越多越好,
+// 同分时优先倒到已有同色水的目标上(空瓶留给真正需要的时候), 倒过去
+// 正好完成一瓶的再加分. 没有可行目标时返回错误.
+func (g *WaterBottleGame) AutoPour(from int) (Move, error) {
+	if from < 0 || from >= len(g.Bottles) {
+		return Move{}, ErrInvalidIndex
+	}
+	best, bestScore := -1, 0
+	for to := range g.Bottles {
+		if to == from {
+			continue
+		}
+		ok, amount, _ := g.CanPour(from, to)
+		if !ok {
+			continue
+		}
+		tb := g.Bottles[to]
+		score := 1 + amount*4
+		if !tb.IsEmpty() {
+			score += 2
+		}
+		if !tb.IsEmpty() && len(tb.Water)+amount == tb.Capacity {
+			score += 3
+		}
+		if score > bestScore {
+			best, bestScore = to, score
+		}
+	}
+	if best < 0 {
+		return Move{}, fmt.Errorf("%s没有可行的倒水目标", g.ContainerName(from))
+	}
+	amount, err := g.Pour(from, best)
+	if err != nil {
+		return Move{}, err
+	}
+	return Move{From: from, To: best, Amount: amount}, nil
+}
+
+// Moves 返回合法走子的惰性迭代器: 每次调用产出下一步合法走子(带可倒
+// 层数), 走完时ok为false. 枚举按需推进, 只要前几个候选的调用方(机器人
+// 选步, 死局探测到第一步就够)不用付全量枚举的开销. 迭代期间改动局面
+// 后继续迭代, 产出的走子以新局面为准.
+func (g *WaterBottleGame) Moves() func() (Move, bool) {
+	from, to := 0, -1
+	return func() (Move, bool) {
+		for {
+			to++
+			if to >= len(g.Bottles) {
+				from++
+				to = 0
+			}
+			if from >= len(g.Bottles) {
+				return Move{}, false
+			}
+			if from == to {
+				continue
+			}
+			if ok, amount, _ := g.CanPour(from, to); ok {
+				return Move{From: from, To: to, Amount: amount}, true
+			}
+		}
+	}
+}
+
+// CheckPossibleMoves 枚举当前所有可行的倒水操作, 返回中文描述列表.
+// 基于ForEachLegalMove实现, 不再为每个from/to组合复制整个游戏状态.
+func (g *WaterBottleGame) CheckPossibleMoves() []string {
+	var moves []string
+	g.ForEachLegalMove(func(m Move) bool {
+		moves = append(moves, fmt.Sprintf("%s -> %s (%s)",
+			g.ContainerName(m.From), g.ContainerName(m.To), getColorName(g.Bottles[m.From].TopColor())))
+		return true
+	})
+	return moves
+}
+
+// ContainerName 容器的显示名称
+func (g *WaterBottleGame) ContainerName(i int) string {
+	if i >= g.BottleCount {
+		return fmt.Sprintf("罐子%d", i)
+	}
+	return fmt.Sprintf("瓶子%d", i)
+}
+
+// copyGameState 复制当前游戏状态快照
+func (g *WaterBottleGame) copyGameState() *gameSnapshot {
+	snap := &gameSnapshot{}
+	g.fillSnapshot(snap)
+	return snap
+}
+
+// fillSnapshot 把当前状态写进快照, 复用快照里已有的切片和map.
+// copyGameState和快照池(见pool.go)共用这一份复制逻辑.
+func (g *WaterBottleGame) fillSnapshot(snap *gameSnapshot) {
+	snap.moveCount = g.MoveCount
+	snap.historyLen = len(g.history)
+	snap.moveLogLen = len(g.moveLog)
+	snap.coins = g.coins
+	snap.bombFuse = g.bombFuse
+	snap.hash = g.hash
+	snap.bottles = snap.bottles[:0]
+	for _, b := range g.Bottles {
+		snap.bottles = append(snap.bottles, packBottle(b))
+	}
+	snap.bags = snap.bags[:0]
+	for _, bag := range g.Bags {
+		snap.bags = append(snap.bags, *bag)
+	}
+	if snap.collected == nil {
+		snap.collected = make(map[int]bool)
+	} else {
+		clear(snap.collected)
+	}
+	for c := range g.collectedColors {
+		snap.collected[c] = true
+	}
+	snap.bagQueue = append(snap.bagQueue[:0], g.bagQueue...)
+}
+
+// restoreGameState 恢复到指定快照, 尽量原地复用已有的容器对象
+func (g *WaterBottleGame) restoreGameState(snap *gameSnapshot) {
+	if len(g.Bottles) > len(snap.bottles) {
+		g.Bottles = g.Bottles[:len(snap.bottles)]
+	}
+	for len(g.Bottles) < len(snap.bottles) {
+		g.Bottles = append(g.Bottles, &Bottle{})
+	}
+	for i := range snap.bottles {
+		snap.bottles[i].unpackInto(g.Bottles[i])
+	}
+	if len(g.Bags) > len(snap.bags) {
+		g.Bags = g.Bags[:len(snap.bags)]
+	}
+	for len(g.Bags) < len(snap.bags) {
+		g.Bags = append(g.Bags, &Bag{})
+	}
+	for i := range snap.bags {
+		*g.Bags[i] = snap.bags[i]
+	}
+	g.collectedColors = make(map[int]bool)
+	for c := range snap.collected {
+		g.collectedColors[c] = true
+	}
+	g.MoveCount = snap.moveCount
+	g.coins = snap.coins
+	g.bombFuse = snap.bombFuse
+	g.hash = snap.hash
+	g.bagQueue = append(g.bagQueue[:0], snap.bagQueue...)
+	if len(g.history) > snap.historyLen {
+		g.history = g.history[:snap.historyLen]
+	}
+	if len(g.moveLog) > snap.moveLogLen {
+		g.moveLog = g.moveLog[:snap.moveLogLen]
+	}
+}
+
+// pushHistory 在改变状态前记录快照. 真实落子会让之前撤销出来的
+// 重做分支作废, 顺手清掉重做栈.
+func (g *WaterBottleGame) pushHistory() {
+	g.history = append(g.history, g.copyGameState())
+	if !g.suppressEvents && len(g.redoStack) > 0 {
+		g.redoStack = g.redoStack[:0]
+	}
+}
+
+// Undo 撤销上一步, 没有可撤销的步数时返回false
+func (g *WaterBottleGame) Undo() bool {
+	if len(g.history) == 0 {
+		return false
+	}
+	snap := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	snap.historyLen = len(g.history)
+	if !g.suppressEvents {
+		// 被撤掉的状态垫进重做栈, 见restart.go
+		g.redoStack = append(g.redoStack, g.copyGameState())
+	}
+	g.restoreGameState(snap)
+	if !g.suppressEvents {
+		g.stats.Undos++
+		g.combo = 0
+		g.addScore(-g.scoring.UndoPenalty)
+	}
+	g.revealCurrentTops()
+	return true
+}
+
+// maxSolverStates 基础的求解状态预算, 具体算法的预算在chooseSolverBudget里放大
+const maxSolverStates = 1000
+
+// Solve 求解当前局面, 返回一条获胜路径.
+// 算法按棋盘规模自动调度(见chooseSolverBudget), 模拟期间屏蔽事件.
+func (g *WaterBottleGame) Solve() ([]Move, bool) {
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	defer func() { g.suppressEvents = prev }()
+	budget := g.chooseSolverBudget()
+	switch budget.algorithm {
+	case "bfs":
+		return g.solveBFS(budget.maxStates)
+	case "bestfirst":
+		return g.solveBestFirst(budget.maxStates)
+	default:
+		if path, ok := g.solveTransposition(budget.maxStates); ok {
+			return path, true
+		}
+		// 置换表预算耗尽还没搜到, 退回贪心碰碰运气
+		return g.solveGreedy(300)
+	}
+}
+
+// UndoToLastCheckpoint 一键回退到最近一次被求解器确认可解的局面.
+// 检查点在Solve/GetHint成功时顺带记录, 没有检查点时返回false.
+func (g *WaterBottleGame) UndoToLastCheckpoint() bool {
+	if g.safeCheckpoint == nil {
+		return false
+	}
+	g.restoreGameState(g.safeCheckpoint)
+	return true
+}
+
+// GetHint 返回求解器建议的下一步, 没有建议时返回false
+func (g *WaterBottleGame) GetHint() (Move, bool) {
+	if err := g.spendCoins("提示", HintCost); err != nil {
+		g.logger.Logf("❌ %v", err)
+		return Move{}, false
+	}
+	g.stats.Hints++
+	g.addScore(-g.scoring.HintPenalty)
+	moves, ok := g.Solve()
+	if ok && len(moves) > 0 {
+		return moves[0], true
+	}
+	return Move{}, false
+}
+
+// GetHintWithTemperature 带温度的提示. 温度为0时等价于GetHint;
+// 温度越高, 允许的"接近最优"余量越大, 并在这些候选步里随机挑一步,
+// 反复求提示的玩家可以看到不同的解法思路而不是同一步.
+func (g *WaterBottleGame) GetHintWithTemperature(temperature float64) (Move, bool) {
+	if temperature <= 0 {
+		return g.GetHint()
+	}
+	if err := g.spendCoins("提示", HintCost); err != nil {
+		g.logger.Logf("❌ %v", err)
+		return Move{}, false
+	}
+	g.stats.Hints++
+	g.addScore(-g.scoring.HintPenalty)
+	base, ok := g.Solve()
+	if !ok || len(base) == 0 {
+		return Move{}, false
+	}
+	optimal := len(base)
+	slack := int(temperature*3 + 0.5)
+
+	prev := g.suppressEvents
+	g.suppressEvents = true
+	start := g.borrowSnapshot()
+	var candidates []Move
+	for from := 0; from < len(g.Bottles); from++ {
+		for to := 0; to < len(g.Bottles); to++ {
+			if from == to {
+				continue
+			}
+			g.restoreGameState(start)
+			if _, err := g.Pour(from, to); err != nil {
+				continue
+			}
+			m := Move{From: from, To: to}
+			if g.IsWon() {
+				candidates = append(candidates, m)
+				continue
+			}
+			if sub, ok := g.Solve(); ok && 1+len(sub) <= optimal+slack {
+				candidates = append(candidates, m)
+			}
+		}
+	}
+	g.restoreGameState(start)
+	releaseSnapshot(start)
+	g.suppressEvents = prev
+	if len(candidates) == 0 {
+		return base[0], true
+	}
+	return candidates[g.rng.Intn(len(candidates))], true
+}
+
+// calculateDifficulty 用瓶子数*颜色数*容量粗略估算难度
+func (g *WaterBottleGame) calculateDifficulty() string {
+	score := g.BottleCount * g.ColorCount * g.Capacity
+	switch {
+	case score <= 48:
+		return "简单"
+	case score <= 120:
+		return "中等"
+	default:
+		return "困难"
+	}
+}
+
+// Difficulty 返回当前谜题的难度描述
+func (g *WaterBottleGame) Difficulty() string {
+	return g.calculateDifficulty()
+}
+
+// AddEmptyBottle 在普通瓶子末尾加一个空瓶
+func (g *WaterBottleGame) AddEmptyBottle() {
+	if err := g.spendCoins("加空瓶", EmptyBottleCost); err != nil {
+		g.logger.Logf("❌ %v", err)
+		return
+	}
+	nb := &Bottle{Capacity: g.Capacity}
+	bottles := append([]*Bottle(nil), g.Bottles[:g.BottleCount]...)
+	bottles = append(bottles, nb)
+	bottles = append(bottles, g.Bottles[g.BottleCount:]...)
+	g.Bottles = bottles
+	g.BottleCount++
+	g.logger.Logf("➕ 新增空瓶, 现在共有%d个瓶子", g.BottleCount)
+}
+
+// AddEmptyJar 在场上追加一个指定容量的空罐子, 和AddEmptyBottle一样
+// 不进撤销历史. 罐子的容量互相独立(存在各自的Bottle.Capacity上,
+// 倒水校验用的就是它), 不要求等于构造时的JarCapacity.
+// capacity<=0时沿用JarCapacity, 没设置过罐子时退回瓶子容量.
+func (g *WaterBottleGame) AddEmptyJar(capacity int) {
+	if capacity <= 0 {
+		capacity = g.JarCapacity
+		if capacity <= 0 {
+			capacity = g.Capacity
+		}
+	}
+	if capacity > MaxCapacity {
+		capacity = MaxCapacity
+	}
+	g.Bottles = append(g.Bottles, &Bottle{Capacity: capacity, IsJar: true})
+	g.JarCount++
+	g.logger.Logf("➕ 新增容量%d的空罐子, 现在共有%d个罐子", capacity, g.JarCount)
+}
+
+// ShuffleWater 把未收集瓶子里的水重新随机分配
+func (g *WaterBottleGame) ShuffleWater() {
+	if err := g.spendCoins("洗牌", ShuffleCost); err != nil {
+		g.logger.Logf("❌ %v", err)
+		return
+	}
+	g.par = 0
+	var pool []int
+	var targets []int
+	for i := 0; i < g.BottleCount; i++ {
+		b := g.Bottles[i]
+		if b.Collected {
+			continue
+		}
+		pool = append(pool, b.Water...)
+		b.Water = b.Water[:0]
+		targets = append(targets, i)
+	}
+	g.auditShuffle("洗牌:打乱", len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+	for _, c := range pool {
+		var candidates []int
+		for _, i := range targets {
+			if !g.Bottles[i].IsFull() {
+				candidates = append(candidates, i)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		idx := candidates[g.auditIntn("洗牌:选瓶", len(candidates))]
+		g.Bottles[idx].Water = append(g.Bottles[idx].Water, c)
+	}
+	if g.Mystery {
+		// 洗牌后层的位置全变了, 揭示状态重新来过
+		g.SetMysteryMode(true)
+	}
+	g.logger.Logf("🔀 洗牌完成")
+}
+
+// GetState 返回所有容器当前的水层颜色
+func (g *WaterBottleGame) GetState() [][]int {
+	var state [][]int
+	for _, b := range g.Bottles {
+		state = append(state, append([]int(nil), b.Water...))
+	}
+	return state
+}
+
+// barScaleThreshold 容量超过该值时改用压缩的比例条
+const barScaleThreshold = 10
+
+// scaledBarWidth 压缩比例条的宽度, 必须是4的倍数以便画刻度
+const scaledBarWidth = 12
+
+// renderBar 渲染容量条. 小容量逐格画块; 大容量按比例压缩到固定宽度,
+// 每1/4处加刻度并附上百分比, 精确数量由调用方以 n/m 形式展示.
+func renderBar(fill, capacity int) string {
+	if capacity <= barScaleThreshold {
+		return strings.Repeat("█", fill) + strings.Repeat("░", capacity-fill)
+	}
+	filled := fill * scaledBarWidth / capacity
+	var sb strings.Builder
+	quarter := scaledBarWidth / 4
+	for i := 0; i < scaledBarWidth; i++ {
+		if i > 0 && i%quarter == 0 {
+			sb.WriteString("┊")
+		}
+		if i < filled {
+			sb.WriteString("█")
+		} else {
+			sb.WriteString("░")
+		}
+	}
+	return fmt.Sprintf("%s %d%%", sb.String(), fill*100/capacity)
+}
+
+// columnarThreshold 瓶子超过该数量时PrintState自动换紧凑布局
+const columnarThreshold = 12
+
+// PrintState 打印当前游戏状态, 即Render到标准输出,
+// 样式跟随SetDisplayStyle, 大盘自动切换紧凑布局
+func (g *WaterBottleGame) PrintState() {
+	opts := DefaultRenderOptions()
+	opts.Style = g.displayStyle
+	opts.Columnar = len(g.Bottles) > columnarThreshold
+	g.Render(os.Stdout, opts)
+}
@@ -0,0 +1,58 @@
+package game
+
+// 万能色(彩虹)水格: 倒水时和任何颜色都算匹配, 判定完成时算作它所在
+// 瓶子的颜色. 倒水搬运的是真实水格, 万能格不会被转成具体颜色,
+// 一直保持彩虹属性.
+
+// WildcardColor 万能色的颜色值. 负数和正常颜色编号(>=1)以及
+// 袋子的"无颜色"(0)都错开.
+const WildcardColor = -2
+
+// colorsCompatible 倒水时两个颜色是否匹配: 相同, 或任一方是万能色
+func colorsCompatible(a, b int) bool {
+	return a == b || a == WildcardColor || b == WildcardColor
+}
+
+// effectiveColor 瓶子的有效颜色: 第一个非万能格的颜色.
+// 空瓶返回0, 全是万能格时返回WildcardColor.
+func effectiveColor(b *Bottle) int {
+	if b.IsEmpty() {
+		return 0
+	}
+	for _, w := range b.Water {
+		if w != WildcardColor {
+			return w
+		}
+	}
+	return WildcardColor
+}
+
+// InjectWildcards 把场上n个随机水格换成万能色, 返回实际替换的格数.
+// 选哪些格由种子决定, 生成阶段调用可保证同种子同布局.
+func (g *WaterBottleGame) InjectWildcards(n int) int {
+	type pos struct{ bottle, layer int }
+	var cells []pos
+	for i, b := range g.Bottles {
+		if b.Collected {
+			continue
+		}
+		for j, w := range b.Water {
+			if w != WildcardColor {
+				cells = append(cells, pos{i, j})
+			}
+		}
+	}
+	g.auditShuffle("万能色:选格", len(cells), func(i, j int) {
+		cells[i], cells[j] = cells[j], cells[i]
+	})
+	if n > len(cells) {
+		n = len(cells)
+	}
+	for _, p := range cells[:n] {
+		g.Bottles[p.bottle].Water[p.layer] = WildcardColor
+	}
+	if n > 0 {
+		g.logger.Logf("🌈 注入了%d格万能色", n)
+	}
+	return n
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 胜利条件插件: IsWon不再硬编码"经典整理/袋子收集"两个分支, 而是
+// 委托给可替换的WinCondition. 不设置时沿用老行为(按UseBags选默认),
+// 存档不保存胜利条件, 由关卡加载方在建局时重新注入.
+
+// WinCondition 一种胜利条件
+type WinCondition interface {
+	Describe() string                  // 条件的中文描述
+	Satisfied(g *WaterBottleGame) bool // 当前局面是否满足
+}
+
+// SetWinCondition 替换本局的胜利条件, nil恢复默认行为
+func (g *WaterBottleGame) SetWinCondition(wc WinCondition) {
+	g.winCond = wc
+}
+
+// WinCondition 本局的胜利条件, 未设置时返回nil
+func (g *WaterBottleGame) WinCondition() WinCondition {
+	return g.winCond
+}
+
+// WinAllSorted 经典条件: 所有容器整理完毕
+type WinAllSorted struct{}
+
+// Describe 条件描述
+func (WinAllSorted) Describe() string { return "把所有瓶子整理完毕" }
+
+// Satisfied 判定
+func (WinAllSorted) Satisfied(g *WaterBottleGame) bool { return g.isSortedState() }
+
+// WinAllCollected 袋子条件: 所有颜色都被袋子收集
+type WinAllCollected struct{}
+
+// Describe 条件描述
+func (WinAllCollected) Describe() string { return "让袋子收集所有颜色" }
+
+// Satisfied 判定
+func (WinAllCollected) Satisfied(g *WaterBottleGame) bool {
+	return len(g.collectedColors) >= g.ColorCount
+}
+
+// WinCollectColors 袋子收集指定的几种颜色即可获胜
+type WinCollectColors struct {
+	Colors []int
+}
+
+// Describe 条件描述
+func (w WinCollectColors) Describe() string {
+	var names []string
+	for _, c := range w.Colors {
+		names = append(names, getColorName(c))
+	}
+	return fmt.Sprintf("让袋子收集%s", strings.Join(names, "/"))
+}
+
+// Satisfied 判定
+func (w WinCollectColors) Satisfied(g *WaterBottleGame) bool {
+	for _, c := range w.Colors {
+		if !g.collectedColors[c] {
+			return false
+		}
+	}
+	return len(w.Colors) > 0
+}
+
+// WinEmptyJars 把所有罐子倒空即可获胜
+type WinEmptyJars struct{}
+
+// Describe 条件描述
+func (WinEmptyJars) Describe() string { return "把所有罐子倒空" }
+
+// Satisfied 判定
+func (WinEmptyJars) Satisfied(g *WaterBottleGame) bool {
+	found := false
+	for _, b := range g.Bottles {
+		if !b.IsJar {
+			continue
+		}
+		found = true
+		if !b.IsEmpty() {
+			return false
+		}
+	}
+	return found
+}
@@ -0,0 +1,150 @@
+package game
+
+import "container/heap"
+
+// Zobrist增量哈希与置换表: CanonicalKey每次要把整个局面编码成
+// 字符串再排序, 大盘上百万级的搜索撑不起来. 这里给局面维护一个
+// uint64哈希: 每个容器单独哈希, 全局取和(求和可交换, 天然对等价
+// 瓶子互换不敏感, 口径和CanonicalKey一致), 倒水只重算两个容器,
+// O(容量)就能得到新键. 置换表直接用map[uint64], 判重成本是常数.
+//
+// 哈希只在一次求解episode内做增量维护: 每次求解入口先整体重算,
+// 之后由pourOne/collectOneBottle/快照恢复来保持同步, 生成和作弊
+// 之类的批量改动不必逐处维护.
+
+// zobristMix splitmix64的终混函数, 把任意64位值打散
+func zobristMix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// bottleHash 单个容器的哈希: 容量, 属性和水层顺序逐层混入.
+// 不含容器下标, 两个内容相同的容器哈希相同.
+func bottleHash(b *Bottle) uint64 {
+	h := zobristMix(uint64(b.Capacity)<<8 | 0x5a)
+	if b.IsJar {
+		h = zobristMix(h ^ 0x1001)
+	}
+	if b.Collected {
+		h = zobristMix(h ^ 0x2002)
+	}
+	for _, w := range b.Water {
+		h = zobristMix(h ^ uint64(colorByte(w)))
+	}
+	return h
+}
+
+// bagHash 袋子部分的哈希, 袋子有序所以混入下标
+func bagHash(i int, bag *Bag) uint64 {
+	return zobristMix(uint64(i+1)<<16 | uint64(colorByte(bag.Color)))
+}
+
+// rebuildHash 从零重算当前局面的哈希
+func (g *WaterBottleGame) rebuildHash() {
+	var h uint64
+	for _, b := range g.Bottles {
+		h += bottleHash(b)
+	}
+	for i, bag := range g.Bags {
+		h += bagHash(i, bag)
+	}
+	g.hash = h
+}
+
+// ttNode 置换表搜索的节点. 不存局面快照, 只存父指针和走子,
+// 展开时从根重放得到局面, 百万级节点的内存才压得住.
+type ttNode struct {
+	parent *ttNode
+	move   Move
+	depth  int
+	score  int
+}
+
+// ttHeap 按score从小到大的优先队列
+type ttHeap []*ttNode
+
+func (h ttHeap) Len() int            { return len(h) }
+func (h ttHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h ttHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *ttHeap) Push(x interface{}) { *h = append(*h, x.(*ttNode)) }
+func (h *ttHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// nodePath 从根到节点的走子序列
+func nodePath(n *ttNode) []Move {
+	if n.parent == nil {
+		return nil
+	}
+	return append(nodePath(n.parent), n.move)
+}
+
+// solveTransposition 带置换表的最佳优先搜索: 判重全靠增量哈希,
+// 节点只存走子链, 大盘上能探索几百万个状态. 不保证最短解.
+func (g *WaterBottleGame) solveTransposition(maxStates int) ([]Move, bool) {
+	start := g.copyGameState()
+	defer g.restoreGameState(start)
+	g.rebuildHash()
+	startHash := g.hash
+
+	visited := make(map[uint64]bool)
+	h := &ttHeap{{score: g.stateScore()}}
+	explored := 0
+	for h.Len() > 0 && explored < maxStates {
+		if g.cancelled() != nil {
+			return nil, false
+		}
+		cur := heap.Pop(h).(*ttNode)
+		// 从根重放到当前节点
+		g.restoreGameState(start)
+		g.hash = startHash
+		ok := true
+		for _, m := range nodePath(cur) {
+			if _, err := g.Pour(m.From, m.To); err != nil {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		if g.IsWon() {
+			g.safeCheckpoint = start
+			return nodePath(cur), true
+		}
+		if visited[g.hash] {
+			continue
+		}
+		visited[g.hash] = true
+		explored++
+		snap := g.borrowSnapshot()
+		base := g.hash
+		for from := 0; from < len(g.Bottles); from++ {
+			for to := 0; to < len(g.Bottles); to++ {
+				if from == to {
+					continue
+				}
+				g.restoreGameState(snap)
+				g.hash = base
+				if _, err := g.Pour(from, to); err != nil {
+					continue
+				}
+				if visited[g.hash] {
+					continue
+				}
+				heap.Push(h, &ttNode{parent: cur, move: Move{From: from, To: to},
+					depth: cur.depth + 1, score: g.stateScore() + cur.depth + 1})
+			}
+		}
+		releaseSnapshot(snap)
+	}
+	return nil, false
+}
@@ -0,0 +1,115 @@
+// Package pack 谜题包: 把一组谜题连同元数据打包导出, 供战役/分享使用.
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/iCode-five/hello-word/internal/game"
+)
+
+// Level 谜题包里的一关
+type Level struct {
+	Name         string  `json:"name"`
+	Seed         int64   `json:"seed"`
+	BottleCount  int     `json:"bottleCount"`
+	Capacity     int     `json:"capacity"`
+	ColorCount   int     `json:"colorCount"`
+	EmptyBottles int     `json:"emptyBottles"`
+	JarCount     int     `json:"jarCount"`
+	JarCapacity  int     `json:"jarCapacity"`
+	UseBags      bool    `json:"useBags"`
+	Difficulty   string  `json:"difficulty,omitempty"` // 难度标签, 生成时估算
+	Bottles      [][]int `json:"bottles"`              // 初始局面, 自底向上
+
+	// AuthorSolution 作者解法: 由重放转换而来的关卡带上原局的完整走子,
+	// 玩家卡关时可以对照, 包浏览器也能用它标注"已验证可解".
+	AuthorSolution []game.Move `json:"authorSolution,omitempty"`
+}
+
+// Pack 一个谜题包
+type Pack struct {
+	Name   string  `json:"name"`
+	Levels []Level `json:"levels"`
+}
+
+// FromGame 把一局游戏的当前局面提取成关卡定义
+func FromGame(name string, g *game.WaterBottleGame) Level {
+	return Level{
+		Name:         name,
+		Seed:         g.Seed(),
+		BottleCount:  g.BottleCount,
+		Capacity:     g.Capacity,
+		ColorCount:   g.ColorCount,
+		EmptyBottles: g.EmptyBottles,
+		JarCount:     g.JarCount,
+		JarCapacity:  g.JarCapacity,
+		UseBags:      g.UseBags,
+		Difficulty:   g.Difficulty(),
+		Bottles:      g.GetState(),
+	}
+}
+
+// FromReplay 把一份走子日志转换成独立关卡: 初始局面按日志里的种子和
+// g的参数重建, 日志本身作为作者解法附在关卡上. 转换前先完整重放一遍,
+// 日志不合法时直接报错而不是产出一张没法通关验证的关卡.
+func FromReplay(name string, g *game.WaterBottleGame, log game.MoveLog) (Level, error) {
+	if _, err := g.Replay(log); err != nil {
+		return Level{}, err
+	}
+	initial := game.NewWaterBottleGameWithSeed(g.BottleCount, g.Capacity, g.ColorCount,
+		g.EmptyBottles, g.JarCount, g.JarCapacity, g.UseBags, log.Seed)
+	lv := FromGame(name, initial)
+	for _, e := range log.Entries {
+		lv.AuthorSolution = append(lv.AuthorSolution, e.Move)
+	}
+	return lv, nil
+}
+
+// NewGame 按关卡定义还原一局游戏. 先做参数校验, 关卡文件可能来自
+// 外部分享, 坏参数要在这里报清楚原因.
+func (lv Level) NewGame() (*game.WaterBottleGame, error) {
+	if v := game.ValidateParams(lv.BottleCount, lv.Capacity, lv.ColorCount,
+		lv.EmptyBottles, lv.JarCount, lv.JarCapacity); !v.OK {
+		return nil, fmt.Errorf("关卡%q参数不合法: %s", lv.Name, v.Issues[0].Message)
+	}
+	return game.NewWaterBottleGameFromState(lv.BottleCount, lv.Capacity, lv.ColorCount,
+		lv.EmptyBottles, lv.JarCount, lv.JarCapacity, lv.UseBags, lv.Seed, lv.Bottles)
+}
+
+// Export 把谜题包写入目录: pack.json加上每关一张SVG缩略图,
+// 包浏览器(包括演示程序的选关界面)可以直接拿缩略图做预览.
+func (p *Pack) Export(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pack.json"), data, 0o644); err != nil {
+		return err
+	}
+	for i, lv := range p.Levels {
+		thumb := filepath.Join(dir, fmt.Sprintf("level%03d.svg", i+1))
+		if err := os.WriteFile(thumb, lv.ThumbnailSVG(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load 从目录读取谜题包
+func Load(dir string) (*Pack, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "pack.json"))
+	if err != nil {
+		return nil, err
+	}
+	var p Pack
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("谜题包解析失败: %w", err)
+	}
+	return &p, nil
+}
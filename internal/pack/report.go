@@ -0,0 +1,97 @@
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iCode-five/hello-word/internal/storage"
+)
+
+// LevelResult 谜题包里一关的通关结果
+type LevelResult struct {
+	Name    string        `json:"name"`
+	Won     bool          `json:"won"`
+	Moves   int           `json:"moves"`
+	Par     int           `json:"par"` // 标准步数(求解器给出的最短解)
+	Elapsed time.Duration `json:"elapsed"`
+	Stars   int           `json:"stars"`
+}
+
+// Report 整个谜题包打完后的总结
+type Report struct {
+	PackName   string        `json:"packName"`
+	Levels     []LevelResult `json:"levels"`
+	TotalMoves int           `json:"totalMoves"`
+	TotalPar   int           `json:"totalPar"`
+	TotalTime  time.Duration `json:"totalTime"`
+	TotalStars int           `json:"totalStars"`
+}
+
+// starsFor 按步数和标准步数打星: 不超par三星, 1.5倍par内两星, 其余一星
+func starsFor(moves, par int) int {
+	switch {
+	case par > 0 && moves <= par:
+		return 3
+	case par > 0 && moves*2 <= par*3:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// BuildReport 把逐关结果汇总成总结报告
+func BuildReport(packName string, results []LevelResult) Report {
+	r := Report{PackName: packName}
+	for _, lv := range results {
+		if lv.Stars == 0 && lv.Won {
+			lv.Stars = starsFor(lv.Moves, lv.Par)
+		}
+		r.Levels = append(r.Levels, lv)
+		r.TotalMoves += lv.Moves
+		r.TotalPar += lv.Par
+		r.TotalTime += lv.Elapsed
+		r.TotalStars += lv.Stars
+	}
+	return r
+}
+
+// Render 渲染"战役总结"文案
+func (r Report) Render() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=== 战役总结: %s ===\n", r.PackName)
+	for i, lv := range r.Levels {
+		status := "✅"
+		if !lv.Won {
+			status = "❌"
+		}
+		fmt.Fprintf(&sb, "第%d关 %s %s: %d步 (标准%d步) 用时%s %s\n",
+			i+1, lv.Name, status, lv.Moves, lv.Par, lv.Elapsed.Round(time.Second), strings.Repeat("⭐", lv.Stars))
+	}
+	fmt.Fprintf(&sb, "合计: %d步 (标准%d步), 用时%s, %d颗星\n",
+		r.TotalMoves, r.TotalPar, r.TotalTime.Round(time.Second), r.TotalStars)
+	return sb.String()
+}
+
+// Save 把总结持久化到玩家档案存储里
+func (r Report) Save(st storage.Storage) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return st.Save(fmt.Sprintf("pack_report_%s.json", r.PackName), data)
+}
+
+// LoadReport 读取之前保存的总结
+func LoadReport(st storage.Storage, packName string) (Report, error) {
+	data, err := st.Load(fmt.Sprintf("pack_report_%s.json", packName))
+	if err != nil {
+		return Report{}, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Report{}, fmt.Errorf("总结解析失败: %w", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,59 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// thumbColors 缩略图里每种颜色对应的十六进制色值, 下标0不用
+var thumbColors = []string{"", "#e53935", "#1e88e5", "#43a047", "#fdd835", "#8e24aa", "#fb8c00", "#00acc1", "#ec407a", "#6d4c41", "#9e9e9e"}
+
+// thumbColor 颜色编号到色值, 超出调色板的颜色用灰色兜底
+func thumbColor(c int) string {
+	if c <= 0 || c >= len(thumbColors) {
+		return "#616161"
+	}
+	return thumbColors[c]
+}
+
+// 缩略图的尺寸参数(像素)
+const (
+	thumbBottleW = 14 // 每个瓶子的宽度
+	thumbLayerH  = 10 // 每层水的高度
+	thumbGap     = 6  // 瓶子之间的间距
+	thumbMargin  = 8  // 画布边距
+)
+
+// ThumbnailSVG 画出关卡初始局面的小缩略图
+func (lv Level) ThumbnailSVG() []byte {
+	maxCap := lv.Capacity
+	if lv.JarCount > 0 && lv.JarCapacity > maxCap {
+		maxCap = lv.JarCapacity
+	}
+	n := len(lv.Bottles)
+	width := thumbMargin*2 + n*thumbBottleW + (n-1)*thumbGap
+	height := thumbMargin*2 + maxCap*thumbLayerH
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fafafa"/>`+"\n", width, height)
+	for i, water := range lv.Bottles {
+		cap := lv.Capacity
+		if i >= lv.BottleCount {
+			cap = lv.JarCapacity
+		}
+		x := thumbMargin + i*(thumbBottleW+thumbGap)
+		top := thumbMargin + (maxCap-cap)*thumbLayerH
+		// 瓶身轮廓
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#555" stroke-width="1" rx="2"/>`+"\n",
+			x, top, thumbBottleW, cap*thumbLayerH)
+		// 水层自底向上
+		for j, c := range water {
+			y := thumbMargin + (maxCap-1-j)*thumbLayerH
+			fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				x+1, y+1, thumbBottleW-2, thumbLayerH-2, thumbColor(c))
+		}
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
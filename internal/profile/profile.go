@@ -0,0 +1,78 @@
+// Package profile 玩家档案: 跨对局累积的汇总统计,
+// 通过storage.Storage落盘到本地档案文件.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/iCode-five/hello-word/internal/game"
+	"github.com/iCode-five/hello-word/internal/storage"
+)
+
+// profileFile 档案的文件名
+const profileFile = "profile.json"
+
+// Profile 跨对局的汇总统计
+type Profile struct {
+	GamesPlayed   int           `json:"gamesPlayed"`
+	GamesWon      int           `json:"gamesWon"`
+	TotalMoves    int           `json:"totalMoves"`
+	TotalUndos    int           `json:"totalUndos"`
+	TotalHints    int           `json:"totalHints"`
+	TotalRestarts int           `json:"totalRestarts"`
+	TotalTime     time.Duration `json:"totalTime"`
+	Coins         int           `json:"coins"` // 经济模式下的金币余额
+}
+
+// Record 把一局的统计累加进档案
+func (p *Profile) Record(s game.GameStats, won bool) {
+	p.GamesPlayed++
+	if won {
+		p.GamesWon++
+	}
+	p.TotalMoves += s.Moves
+	p.TotalUndos += s.Undos
+	p.TotalHints += s.Hints
+	p.TotalRestarts += s.Restarts
+	p.TotalTime += s.Elapsed
+}
+
+// WinRate 胜率, 没打过就是0
+func (p Profile) WinRate() float64 {
+	if p.GamesPlayed == 0 {
+		return 0
+	}
+	return float64(p.GamesWon) / float64(p.GamesPlayed)
+}
+
+// AverageMoves 平均每局步数, 没打过就是0
+func (p Profile) AverageMoves() float64 {
+	if p.GamesPlayed == 0 {
+		return 0
+	}
+	return float64(p.TotalMoves) / float64(p.GamesPlayed)
+}
+
+// Load 读取档案; 档案不存在时返回全新的空档案
+func Load(st storage.Storage) (*Profile, error) {
+	data, err := st.Load(profileFile)
+	if err != nil {
+		return &Profile{}, nil
+	}
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("档案解析失败: %w", err)
+	}
+	return &p, nil
+}
+
+// Save 把档案落盘
+func (p *Profile) Save(st storage.Storage) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return st.Save(profileFile, data)
+}
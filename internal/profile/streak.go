@@ -0,0 +1,62 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/iCode-five/hello-word/internal/storage"
+)
+
+// streakFile 连胜记录的文件名
+const streakFile = "daily_streak.json"
+
+// dateLayout 连胜记录里日期的存储格式
+const dateLayout = "2006-01-02"
+
+// Streak 每日谜题的连胜记录
+type Streak struct {
+	LastWin string `json:"lastWin"` // 最近一次通关的UTC日期
+	Current int    `json:"current"` // 当前连胜天数
+	Best    int    `json:"best"`    // 历史最佳连胜
+}
+
+// RecordWin 记录date这天通关了每日谜题: 紧接着昨天则连胜+1,
+// 同一天重复通关不重复计数, 断档则从1重新数起.
+func (s *Streak) RecordWin(date time.Time) {
+	day := date.UTC().Format(dateLayout)
+	switch s.LastWin {
+	case day:
+		return
+	case date.UTC().AddDate(0, 0, -1).Format(dateLayout):
+		s.Current++
+	default:
+		s.Current = 1
+	}
+	s.LastWin = day
+	if s.Current > s.Best {
+		s.Best = s.Current
+	}
+}
+
+// LoadStreak 读取连胜记录; 不存在时返回全新的空记录
+func LoadStreak(st storage.Storage) (*Streak, error) {
+	data, err := st.Load(streakFile)
+	if err != nil {
+		return &Streak{}, nil
+	}
+	var s Streak
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("连胜记录解析失败: %w", err)
+	}
+	return &s, nil
+}
+
+// Save 把连胜记录落盘
+func (s *Streak) Save(st storage.Storage) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return st.Save(streakFile, data)
+}
@@ -0,0 +1,66 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iCode-five/hello-word/internal/storage"
+)
+
+// day 构造UTC日期的便捷函数
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+}
+
+// TestStreakRecordWin 连续天数+1, 同日去重, 断档归1, 最佳保留
+func TestStreakRecordWin(t *testing.T) {
+	var s Streak
+	s.RecordWin(day(2026, 1, 30))
+	s.RecordWin(day(2026, 1, 31))
+	s.RecordWin(day(2026, 2, 1)) // 跨月也算连续
+	if s.Current != 3 {
+		t.Errorf("连续三天应是3连胜, 得到%d", s.Current)
+	}
+	s.RecordWin(day(2026, 2, 1)) // 同一天重复通关不重复计
+	if s.Current != 3 {
+		t.Errorf("同日重复通关不应加连胜, 得到%d", s.Current)
+	}
+	s.RecordWin(day(2026, 2, 5)) // 断档后从1重新数
+	if s.Current != 1 {
+		t.Errorf("断档后应归1, 得到%d", s.Current)
+	}
+	if s.Best != 3 {
+		t.Errorf("历史最佳应保留3, 得到%d", s.Best)
+	}
+}
+
+// TestStreakTimezone 连胜按UTC日期计, 本地时区不影响判定
+func TestStreakTimezone(t *testing.T) {
+	var s Streak
+	east := time.FixedZone("UTC+9", 9*3600)
+	// 当地1月2日早8点 = UTC1月1日23点; 次日当地再赢一次是UTC1月2日
+	s.RecordWin(time.Date(2026, 1, 2, 8, 0, 0, 0, east))
+	s.RecordWin(time.Date(2026, 1, 3, 8, 0, 0, 0, east))
+	if s.Current != 2 {
+		t.Errorf("按UTC应是连续两天, 得到%d", s.Current)
+	}
+	if s.LastWin != "2026-01-02" {
+		t.Errorf("最近通关日应是UTC的2026-01-02, 得到%q", s.LastWin)
+	}
+}
+
+// TestStreakSaveLoad 连胜记录落盘后能原样读回
+func TestStreakSaveLoad(t *testing.T) {
+	st := storage.NewFileStorage(t.TempDir())
+	s := &Streak{LastWin: "2026-08-27", Current: 4, Best: 9}
+	if err := s.Save(st); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+	got, err := LoadStreak(st)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if *got != *s {
+		t.Errorf("读回的记录%+v与保存的%+v不一致", *got, *s)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 访问控制: 公网部署时防止机器人刷/solve. 鉴权用静态API key
+// (X-API-Key头或Bearer token), 限流是按key的令牌桶, 没配key时
+// 按来源IP限. 两者都是可选的, 不配置就完全放行, 本地调试零摩擦.
+
+// rateBucket 一个调用方的令牌桶
+type rateBucket struct {
+	tokens float64   // 剩余令牌
+	last   time.Time // 上次补充时间
+}
+
+// accessControl 鉴权与限流中间件的状态
+type accessControl struct {
+	mu      sync.Mutex
+	keys    map[string]bool        // 合法的API key, 空表示不鉴权
+	perMin  float64                // 每个调用方每分钟的请求配额, 0表示不限流
+	buckets map[string]*rateBucket // 按调用方的令牌桶
+	now     func() time.Time       // 时间源, 方便测试
+}
+
+// SetAPIKeys 配置合法的API key列表, 空列表关闭鉴权
+func (s *Server) SetAPIKeys(keys []string) {
+	s.access.mu.Lock()
+	defer s.access.mu.Unlock()
+	s.access.keys = make(map[string]bool)
+	for _, k := range keys {
+		if k != "" {
+			s.access.keys[k] = true
+		}
+	}
+}
+
+// SetRateLimit 配置每个调用方每分钟的请求配额, 0关闭限流
+func (s *Server) SetRateLimit(perMinute int) {
+	s.access.mu.Lock()
+	defer s.access.mu.Unlock()
+	s.access.perMin = float64(perMinute)
+}
+
+// requestAPIKey 客户端在请求头里出示的API key
+func requestAPIKey(r *http.Request) (string, bool) {
+	if k := r.Header.Get("X-API-Key"); k != "" {
+		return k, true
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer "), true
+	}
+	return "", false
+}
+
+// clientIP 请求的来源IP, 匿名调用方的限流口径
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// 闲置令牌桶的清理口径: 桶多到一定数量时顺手把久不活动的桶删掉,
+// 防止调用方(或伪造的来源)无限增多把map撑爆
+const (
+	bucketIdleTTL   = 10 * time.Minute
+	bucketSweepSize = 1024
+)
+
+// allow 限流判定: 调用方的令牌桶里是否还有配额
+func (a *accessControl) allow(caller string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.perMin <= 0 {
+		return true
+	}
+	now := a.now()
+	if len(a.buckets) >= bucketSweepSize {
+		a.sweep(now)
+	}
+	b := a.buckets[caller]
+	if b == nil {
+		b = &rateBucket{tokens: a.perMin, last: now}
+		a.buckets[caller] = b
+	}
+	b.tokens += now.Sub(b.last).Minutes() * a.perMin
+	if b.tokens > a.perMin {
+		b.tokens = a.perMin
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep 删掉闲置超过TTL的令牌桶, 调用方需持有a.mu
+func (a *accessControl) sweep(now time.Time) {
+	for k, b := range a.buckets {
+		if now.Sub(b.last) > bucketIdleTTL {
+			delete(a.buckets, k)
+		}
+	}
+}
+
+// guard 鉴权+限流中间件. /healthz始终放行, 留给负载均衡探活.
+// 限流桶只认两种身份: 验证通过的API key, 或来源IP — 客户端随手
+// 捏一个没配置过的key头不会拿到新桶, 轮换假key绕不开限流.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, hasKey := requestAPIKey(r)
+		s.access.mu.Lock()
+		needAuth := len(s.access.keys) > 0
+		validKey := hasKey && s.access.keys[key]
+		s.access.mu.Unlock()
+		if needAuth && !validKey {
+			writeError(w, http.StatusUnauthorized, "缺少或无效的API key (X-API-Key头或Bearer token)")
+			return
+		}
+		caller := clientIP(r)
+		if validKey {
+			caller = "key:" + key
+		}
+		if !s.access.allow(caller) {
+			writeError(w, http.StatusTooManyRequests, "请求太频繁, 超出限流配额")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
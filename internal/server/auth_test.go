@@ -0,0 +1,105 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// doReq 向服务器发一个带指定API key头的请求, 返回状态码
+func doReq(t *testing.T, h http.Handler, path, apiKey, remote string) int {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if apiKey != "" {
+		r.Header.Set("X-API-Key", apiKey)
+	}
+	if remote != "" {
+		r.RemoteAddr = remote
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+// TestGuardAuth 配了API key后没出示或出示错key都该401, /healthz放行
+func TestGuardAuth(t *testing.T) {
+	s := New()
+	s.SetAPIKeys([]string{"secret"})
+	h := s.Handler()
+	if code := doReq(t, h, "/metrics", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("无key访问应401, 得到%d", code)
+	}
+	if code := doReq(t, h, "/metrics", "wrong", ""); code != http.StatusUnauthorized {
+		t.Errorf("错key访问应401, 得到%d", code)
+	}
+	if code := doReq(t, h, "/metrics", "secret", ""); code != http.StatusOK {
+		t.Errorf("正确key访问应200, 得到%d", code)
+	}
+	if code := doReq(t, h, "/healthz", "", ""); code != http.StatusOK {
+		t.Errorf("/healthz应放行, 得到%d", code)
+	}
+}
+
+// TestRateLimitPerCaller 限流按调用方独立计数, 配额用完后429
+func TestRateLimitPerCaller(t *testing.T) {
+	s := New()
+	s.SetAPIKeys([]string{"k1", "k2"})
+	s.SetRateLimit(2)
+	h := s.Handler()
+	for i := 0; i < 2; i++ {
+		if code := doReq(t, h, "/metrics", "k1", ""); code != http.StatusOK {
+			t.Fatalf("k1第%d次请求应200, 得到%d", i+1, code)
+		}
+	}
+	if code := doReq(t, h, "/metrics", "k1", ""); code != http.StatusTooManyRequests {
+		t.Errorf("k1超配额应429, 得到%d", code)
+	}
+	// 另一个key的桶不受影响
+	if code := doReq(t, h, "/metrics", "k2", ""); code != http.StatusOK {
+		t.Errorf("k2应不受k1限流影响, 得到%d", code)
+	}
+}
+
+// TestRateLimitNotBypassedByFakeKeys 没配鉴权时轮换假key头不能换新桶,
+// 限流必须按来源IP计
+func TestRateLimitNotBypassedByFakeKeys(t *testing.T) {
+	s := New()
+	s.SetRateLimit(2)
+	h := s.Handler()
+	for i := 0; i < 3; i++ {
+		code := doReq(t, h, "/metrics", fmt.Sprintf("fake%d", i), "1.2.3.4:5678")
+		if i < 2 && code != http.StatusOK {
+			t.Fatalf("第%d次请求应200, 得到%d", i+1, code)
+		}
+		if i == 2 && code != http.StatusTooManyRequests {
+			t.Errorf("轮换假key的第3次请求应429, 得到%d", code)
+		}
+	}
+	// 换一个真实来源IP才有新配额
+	if code := doReq(t, h, "/metrics", "", "5.6.7.8:1234"); code != http.StatusOK {
+		t.Errorf("另一IP应有独立配额, 得到%d", code)
+	}
+}
+
+// TestRateBucketSweep 闲置的令牌桶在数量超限时被清走, map不无限膨胀
+func TestRateBucketSweep(t *testing.T) {
+	s := New()
+	s.SetRateLimit(10)
+	base := time.Now()
+	now := base
+	s.access.now = func() time.Time { return now }
+	for i := 0; i < bucketSweepSize; i++ {
+		s.access.allow(fmt.Sprintf("ip%d", i))
+	}
+	if len(s.access.buckets) < bucketSweepSize {
+		t.Fatalf("铺垫桶数不够: %d", len(s.access.buckets))
+	}
+	// 时间推过TTL后再来一个新调用方, 旧桶应被一扫而空
+	now = base.Add(bucketIdleTTL + time.Minute)
+	s.access.allow("fresh")
+	if len(s.access.buckets) > 2 {
+		t.Errorf("闲置桶应被清理, 还剩%d个", len(s.access.buckets))
+	}
+}
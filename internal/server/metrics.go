@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// 运维指标: 托管实例的/metrics端点, 输出Prometheus文本格式.
+// 指标种类就几个固定的, 不值得为此引入官方client库, 手写文本
+// 导出即可: 计数器(开局数/倒水数/生成尝试数)、活跃会话数的
+// gauge、求解耗时的直方图.
+
+// solveBuckets 求解耗时直方图的桶上界, 单位秒
+var solveBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// Metrics 服务器的运行指标, 并发安全
+type Metrics struct {
+	mu sync.Mutex
+
+	gamesCreated       int64 // 累计开局数
+	poursTotal         int64 // 累计倒水次数
+	poursFailed        int64 // 累计被拒绝的倒水次数
+	generationAttempts int64 // 累计生成尝试次数
+	storeErrors        int64 // 累计持久化失败次数
+	activeSessions     int64 // 当前活跃对局数
+
+	solveCount   int64    // 求解次数
+	solveSeconds float64  // 求解总耗时
+	solveHist    [9]int64 // 各桶的累计次数, 末位是+Inf
+}
+
+// NewMetrics 创建一组清零的指标
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// GameCreated 记一次开局, 同时增加活跃会话
+func (m *Metrics) GameCreated() {
+	m.mu.Lock()
+	m.gamesCreated++
+	m.activeSessions++
+	m.mu.Unlock()
+}
+
+// GameClosed 记一次对局关闭
+func (m *Metrics) GameClosed() {
+	m.mu.Lock()
+	m.activeSessions--
+	m.mu.Unlock()
+}
+
+// Pour 记一次倒水, ok表示这次倒水是否被引擎接受
+func (m *Metrics) Pour(ok bool) {
+	m.mu.Lock()
+	m.poursTotal++
+	if !ok {
+		m.poursFailed++
+	}
+	m.mu.Unlock()
+}
+
+// Generation 记一轮生成, attempts是这轮实际的尝试次数
+func (m *Metrics) Generation(attempts int) {
+	m.mu.Lock()
+	m.generationAttempts += int64(attempts)
+	m.mu.Unlock()
+}
+
+// StoreError 记一次持久化失败
+func (m *Metrics) StoreError() {
+	m.mu.Lock()
+	m.storeErrors++
+	m.mu.Unlock()
+}
+
+// Solve 记一次求解耗时, 单位秒
+func (m *Metrics) Solve(seconds float64) {
+	m.mu.Lock()
+	m.solveCount++
+	m.solveSeconds += seconds
+	i := sort.SearchFloat64s(solveBuckets, seconds)
+	for ; i < len(solveBuckets); i++ {
+		m.solveHist[i]++
+	}
+	m.solveHist[len(solveBuckets)]++
+	m.mu.Unlock()
+}
+
+// WritePrometheus 按Prometheus文本格式输出全部指标
+func (m *Metrics) WritePrometheus(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter := func(name, help string, v int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+	}
+	counter("watersort_games_created_total", "累计开局数", m.gamesCreated)
+	counter("watersort_pours_total", "累计倒水次数", m.poursTotal)
+	counter("watersort_pours_failed_total", "累计被拒绝的倒水次数", m.poursFailed)
+	counter("watersort_generation_attempts_total", "累计生成尝试次数", m.generationAttempts)
+	counter("watersort_store_errors_total", "累计持久化失败次数", m.storeErrors)
+	fmt.Fprintf(w, "# HELP watersort_active_sessions 当前活跃对局数\n")
+	fmt.Fprintf(w, "# TYPE watersort_active_sessions gauge\n")
+	fmt.Fprintf(w, "watersort_active_sessions %d\n", m.activeSessions)
+	fmt.Fprintf(w, "# HELP watersort_solve_duration_seconds 求解耗时\n")
+	fmt.Fprintf(w, "# TYPE watersort_solve_duration_seconds histogram\n")
+	for i, le := range solveBuckets {
+		fmt.Fprintf(w, "watersort_solve_duration_seconds_bucket{le=%q} %d\n",
+			fmt.Sprintf("%g", le), m.solveHist[i])
+	}
+	fmt.Fprintf(w, "watersort_solve_duration_seconds_bucket{le=\"+Inf\"} %d\n",
+		m.solveHist[len(solveBuckets)])
+	fmt.Fprintf(w, "watersort_solve_duration_seconds_sum %g\n", m.solveSeconds)
+	fmt.Fprintf(w, "watersort_solve_duration_seconds_count %d\n", m.solveCount)
+}
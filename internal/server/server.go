@@ -0,0 +1,218 @@
+// Package server 把引擎包装成一个小REST服务: 创建对局、倒水、求解,
+// 外加托管部署需要的运维端点(/metrics等). 对局保存在内存里, 按id
+// 寻址; 接口走JSON, 走子用notation.go的记谱形式, 方便curl调试.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iCode-five/hello-word/internal/game"
+)
+
+// Server 内存态的游戏服务器
+type Server struct {
+	mu      sync.Mutex
+	games   map[string]*game.WaterBottleGame
+	nextID  int
+	metrics *Metrics
+	access  accessControl
+	tracer  Tracer
+	store   GameStore
+	pprofOn bool
+}
+
+// New 创建一个空服务器
+func New() *Server {
+	return &Server{
+		games:   make(map[string]*game.WaterBottleGame),
+		metrics: NewMetrics(),
+		access:  accessControl{buckets: make(map[string]*rateBucket), now: time.Now},
+		tracer:  noopTracer{},
+	}
+}
+
+// Metrics 服务器的指标, 供外部(如CLI定期打印)读取
+func (s *Server) Metrics() *Metrics { return s.metrics }
+
+// gameView 一局游戏对外的JSON形态
+type gameView struct {
+	ID        string  `json:"id"`
+	State     [][]int `json:"state"`
+	MoveCount int     `json:"moveCount"`
+	Score     int     `json:"score"`
+	Result    string  `json:"result"`
+	Won       bool    `json:"won"`
+}
+
+// view 组装一局游戏的对外视图, 调用方需持有s.mu
+func (s *Server) view(id string, g *game.WaterBottleGame) gameView {
+	return gameView{
+		ID:        id,
+		State:     g.GetState(),
+		MoveCount: g.MoveCount,
+		Score:     g.Score(),
+		Result:    string(g.Result()),
+		Won:       g.IsWon(),
+	}
+}
+
+// writeJSON 输出一个JSON响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 输出一个JSON错误响应
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+// Handler 服务器的HTTP路由
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/games", s.handleGames)
+	mux.HandleFunc("/games/", s.handleGame)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	if s.pprofOn {
+		registerPprof(mux)
+	}
+	return s.guard(mux)
+}
+
+// handleGames 集合端点: POST创建新对局
+func (s *Server) handleGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "用POST /games创建对局")
+		return
+	}
+	cfg := game.DefaultConfig()
+	if r.Body != nil {
+		// 空请求体取默认配置, 有内容则按GameConfig解析
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil && err.Error() != "EOF" {
+			writeError(w, http.StatusBadRequest, "配置解析失败: %v", err)
+			return
+		}
+	}
+	// NewGame自带反向生成, 出来就是带好注入的可解谜题,
+	// 不需要(也不应该)再随机生成一遍把它覆盖掉
+	var g *game.WaterBottleGame
+	if err := s.span("generate", func() error {
+		var err error
+		g, err = game.NewGame(cfg)
+		return err
+	}); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	s.metrics.Generation(g.LastGenerationReport().Attempts)
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("g%d", s.nextID)
+	s.games[id] = g
+	v := s.view(id, g)
+	s.persist(id, g)
+	s.mu.Unlock()
+	s.metrics.GameCreated()
+	writeJSON(w, http.StatusCreated, v)
+}
+
+// handleGame 单局端点: /games/{id}[/pour|/solve]
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/games/")
+	id, action, _ := strings.Cut(rest, "/")
+	s.mu.Lock()
+	g, ok := s.games[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "没有id为%q的对局", id)
+		return
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		v := s.view(id, g)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, v)
+	case action == "" && r.Method == http.MethodDelete:
+		s.mu.Lock()
+		delete(s.games, id)
+		s.unpersist(id)
+		s.mu.Unlock()
+		s.metrics.GameClosed()
+		writeJSON(w, http.StatusOK, map[string]string{"status": "closed"})
+	case action == "pour" && r.Method == http.MethodPost:
+		s.handlePour(w, r, id, g)
+	case action == "solve" && r.Method == http.MethodPost:
+		s.handleSolve(w, id, g)
+	default:
+		writeError(w, http.StatusNotFound, "不支持的操作: %s %s", r.Method, r.URL.Path)
+	}
+}
+
+// handlePour 执行一步倒水, 请求体形如{"move":"B0>B3"}
+func (s *Server) handlePour(w http.ResponseWriter, r *http.Request, id string, g *game.WaterBottleGame) {
+	var req struct {
+		Move string `json:"move"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "请求解析失败: %v", err)
+		return
+	}
+	s.mu.Lock()
+	err := s.span("pour", func() error {
+		_, err := g.PlayNotation(req.Move)
+		return err
+	})
+	v := s.view(id, g)
+	if err == nil {
+		s.persist(id, g)
+	}
+	s.mu.Unlock()
+	s.metrics.Pour(err == nil)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// handleSolve 对当前局面求解, 返回记谱形式的走子序列
+func (s *Server) handleSolve(w http.ResponseWriter, id string, g *game.WaterBottleGame) {
+	s.mu.Lock()
+	start := time.Now()
+	var moves []game.Move
+	var ok bool
+	s.span("solve", func() error {
+		moves, ok = g.Solve()
+		return nil
+	})
+	elapsed := time.Since(start)
+	var steps []string
+	for _, m := range moves {
+		steps = append(steps, g.FormatMove(m))
+	}
+	s.mu.Unlock()
+	s.metrics.Solve(elapsed.Seconds())
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "当前局面无解")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id": id, "moves": steps, "elapsedMs": elapsed.Milliseconds(),
+	})
+}
+
+// handleMetrics Prometheus文本格式的运行指标
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.WritePrometheus(w)
+}
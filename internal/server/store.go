@@ -0,0 +1,67 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/iCode-five/hello-word/internal/game"
+)
+
+// GameStore 对局的持久化后端. internal/sqlstore的Store实现了它;
+// 用接口解耦是沿用storage.Storage的做法, server不直接依赖具体数据库.
+type GameStore interface {
+	SaveGame(id string, g *game.WaterBottleGame) error
+	LoadGame(id string) (*game.WaterBottleGame, error)
+	ListGames() ([]string, error)
+	DeleteGame(id string) error
+}
+
+// SetStore 挂接持久化后端, 并把库里已有的对局全部恢复进内存,
+// 让会话跨进程重启存活. 返回恢复的局数.
+func (s *Server) SetStore(store GameStore) (int, error) {
+	ids, err := store.ListGames()
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	restored := 0
+	for _, id := range ids {
+		g, err := store.LoadGame(id)
+		if err != nil {
+			// 个别损坏的记录不拦住整个启动, 跳过即可
+			continue
+		}
+		s.games[id] = g
+		restored++
+		// 编号接着库里最大的往后发, 避免新局覆盖旧局
+		if n, err := strconv.Atoi(strings.TrimPrefix(id, "g")); err == nil && n > s.nextID {
+			s.nextID = n
+		}
+	}
+	return restored, nil
+}
+
+// persist 把一局对局落库, 调用方需持有s.mu: SaveGame会读取活的
+// 对局状态, 不拿锁落库会和并发的倒水请求赛跑. 持久化是尽力而为:
+// 数据库抖动不应该让一次本已成功的走子对客户端报失败, 所以错误
+// 只计数不上抛.
+func (s *Server) persist(id string, g *game.WaterBottleGame) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.SaveGame(id, g); err != nil {
+		s.metrics.StoreError()
+	}
+}
+
+// unpersist 把一局对局从库里删掉, 加锁和报错口径与persist一致
+func (s *Server) unpersist(id string) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.DeleteGame(id); err != nil {
+		s.metrics.StoreError()
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/iCode-five/hello-word/internal/game"
+)
+
+// memStore 内存实现的GameStore, 测试里顶替SQLite
+type memStore struct {
+	saves map[string][]byte
+}
+
+func newMemStore() *memStore { return &memStore{saves: make(map[string][]byte)} }
+
+func (m *memStore) SaveGame(id string, g *game.WaterBottleGame) error {
+	data, err := g.SaveData()
+	if err != nil {
+		return err
+	}
+	m.saves[id] = data
+	return nil
+}
+
+func (m *memStore) LoadGame(id string) (*game.WaterBottleGame, error) {
+	return game.LoadGameFromData(m.saves[id])
+}
+
+func (m *memStore) ListGames() ([]string, error) {
+	var ids []string
+	for id := range m.saves {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (m *memStore) DeleteGame(id string) error {
+	delete(m.saves, id)
+	return nil
+}
+
+// TestStoreSurvivesRestart 挂了持久化后端的服务器重启(换一个新Server
+// 挂同一个store)后, 旧对局还在, 编号也接着往后发
+func TestStoreSurvivesRestart(t *testing.T) {
+	store := newMemStore()
+	s1 := New()
+	if _, err := s1.SetStore(store); err != nil {
+		t.Fatalf("挂接store失败: %v", err)
+	}
+	h1 := s1.Handler()
+	w := httptest.NewRecorder()
+	h1.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/games", strings.NewReader(`{"seed":7}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("建局应201, 得到%d: %s", w.Code, w.Body.String())
+	}
+	if len(store.saves) != 1 {
+		t.Fatalf("建局后库里应有1局, 实际%d", len(store.saves))
+	}
+
+	// "重启": 新Server挂同一个store, 对局应被恢复
+	s2 := New()
+	restored, err := s2.SetStore(store)
+	if err != nil {
+		t.Fatalf("重启恢复失败: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("应恢复1局, 实际%d", restored)
+	}
+	h2 := s2.Handler()
+	w = httptest.NewRecorder()
+	h2.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/games/g1", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("恢复的对局应可访问, 得到%d: %s", w.Code, w.Body.String())
+	}
+	// 新建的局要接着编号g2, 不能覆盖g1
+	w = httptest.NewRecorder()
+	h2.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/games", strings.NewReader(`{"seed":8}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("重启后建局应201, 得到%d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"id":"g2"`) {
+		t.Errorf("重启后的新局应编号g2: %s", w.Body.String())
+	}
+
+	// DELETE要把库里的记录也删掉
+	w = httptest.NewRecorder()
+	h2.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/games/g1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("删除对局应200, 得到%d", w.Code)
+	}
+	if _, ok := store.saves["g1"]; ok {
+		t.Errorf("删除后库里不应再有g1")
+	}
+}
+
+// TestPersistConcurrentPours 挂着store并发倒水不能和落库赛跑,
+// 用-race跑这个测试验证持久化在锁内读取对局状态
+func TestPersistConcurrentPours(t *testing.T) {
+	s := New()
+	if _, err := s.SetStore(newMemStore()); err != nil {
+		t.Fatalf("挂接store失败: %v", err)
+	}
+	h := s.Handler()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/games", strings.NewReader(`{"seed":3}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("建局应201, 得到%d", w.Code)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				body := strings.NewReader(fmt.Sprintf(`{"move":"B%d>B%d"}`, n%6, (n+j)%6))
+				h.ServeHTTP(httptest.NewRecorder(),
+					httptest.NewRequest(http.MethodPost, "/games/g1/pour", body))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
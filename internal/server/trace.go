@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// 性能诊断: 生产环境里定位求解器/生成器的性能回归. pprof端点
+// 按需开启(挂在/debug/pprof/下, 记得配合鉴权用); 关键操作
+// (倒水/求解/生成)外包了一层span钩子, 接口对齐OpenTelemetry的
+// 开始/结束语义, 部署方可以用几行适配代码把span转发给任何
+// otel exporter, 引擎本身不背SDK依赖.
+
+// Tracer 追踪钩子: StartSpan返回结束回调, 结束时带上操作耗时
+// 与错误(成功为nil). 实现须并发安全.
+type Tracer interface {
+	StartSpan(name string) func(elapsed time.Duration, err error)
+}
+
+// noopTracer 默认实现, 什么都不记
+type noopTracer struct{}
+
+// StartSpan 返回空回调
+func (noopTracer) StartSpan(string) func(time.Duration, error) {
+	return func(time.Duration, error) {}
+}
+
+// SetTracer 接入追踪钩子, nil恢复为不追踪
+func (s *Server) SetTracer(t Tracer) {
+	if t == nil {
+		t = noopTracer{}
+	}
+	s.tracer = t
+}
+
+// span 包住一段操作: 开span, 执行, 按耗时和错误收尾
+func (s *Server) span(name string, op func() error) error {
+	end := s.tracer.StartSpan(name)
+	start := time.Now()
+	err := op()
+	end(time.Since(start), err)
+	return err
+}
+
+// EnablePprof 在路由上开启net/http/pprof端点. 这些端点会暴露
+// 堆栈和内存细节, 公网部署务必同时配API key.
+func (s *Server) EnablePprof() {
+	s.pprofOn = true
+}
+
+// registerPprof 把pprof的各个handler挂到/debug/pprof/下
+func registerPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
@@ -0,0 +1,81 @@
+// Package session 命名存档槽: 让玩家同时保留多个进行中的谜题,
+// 按名字保存/读取/列举. 存档内容就是引擎的SaveData JSON.
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iCode-five/hello-word/internal/game"
+	"github.com/iCode-five/hello-word/internal/storage"
+)
+
+// 存档槽的文件命名: save_<名字>.json, 和玩家档案等其他文件区分开
+const (
+	savePrefix = "save_"
+	saveExt    = ".json"
+)
+
+// Manager 存档槽管理器
+type Manager struct {
+	store storage.Storage
+}
+
+// NewManager 在给定存储上创建存档管理器
+func NewManager(store storage.Storage) *Manager {
+	return &Manager{store: store}
+}
+
+// checkName 存档名不能为空, 也不能带路径分隔符之类的危险字符
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("存档名不能为空")
+	}
+	if strings.ContainsAny(name, `/\.`) {
+		return fmt.Errorf("存档名%q不能包含 / \\ . 字符", name)
+	}
+	return nil
+}
+
+// SaveAs 把一局游戏存进指定名字的槽位, 同名旧档被覆盖
+func (m *Manager) SaveAs(name string, g *game.WaterBottleGame) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	data, err := g.SaveData()
+	if err != nil {
+		return err
+	}
+	return m.store.Save(savePrefix+name+saveExt, data)
+}
+
+// Load 按名字读取一局存档
+func (m *Manager) Load(name string) (*game.WaterBottleGame, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	data, err := m.store.Load(savePrefix + name + saveExt)
+	if err != nil {
+		return nil, fmt.Errorf("没有名为%q的存档: %w", name, err)
+	}
+	return game.LoadGameFromData(data)
+}
+
+// ListSaves 列出所有存档槽的名字
+func (m *Manager) ListSaves() ([]string, error) {
+	l, ok := m.store.(storage.Lister)
+	if !ok {
+		return nil, fmt.Errorf("存储不支持列举存档")
+	}
+	files, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if strings.HasPrefix(f, savePrefix) && strings.HasSuffix(f, saveExt) {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(f, savePrefix), saveExt))
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,180 @@
+// Package sqlstore 可选的SQLite持久化: 服务器模式把对局, 走子和玩家
+// 档案落进数据库, 进程重启后会话不丢, 历史对局也能用SQL做统计.
+// 驱动用纯Go的modernc.org/sqlite, 不需要cgo.
+package sqlstore
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/iCode-five/hello-word/internal/game"
+)
+
+// schema 建表语句, Open时幂等执行
+const schema = `
+CREATE TABLE IF NOT EXISTS games (
+	id         TEXT PRIMARY KEY,
+	data       TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS moves (
+	game_id     TEXT NOT NULL,
+	seq         INTEGER NOT NULL,
+	from_bottle INTEGER NOT NULL,
+	to_bottle   INTEGER NOT NULL,
+	amount      INTEGER NOT NULL,
+	played_at   TIMESTAMP NOT NULL,
+	PRIMARY KEY (game_id, seq)
+);
+CREATE TABLE IF NOT EXISTS blobs (
+	name TEXT PRIMARY KEY,
+	data BLOB NOT NULL
+);
+`
+
+// Store SQLite后端. 它同时实现storage.Storage和storage.Lister,
+// 玩家档案/连胜记录/存档槽这些走Storage接口的模块可以直接换用它.
+type Store struct {
+	db *sql.DB
+}
+
+// Open 打开(或创建)数据库文件并准备好表结构
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化表结构失败: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveGame 保存(或更新)一局对局: 存档JSON整体落库, 走子日志
+// 重写进moves表, 供后续按局或跨局统计.
+func (s *Store) SaveGame(id string, g *game.WaterBottleGame) error {
+	data, err := g.SaveData()
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO games (id, data, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET data=excluded.data, updated_at=CURRENT_TIMESTAMP`, id, string(data)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM moves WHERE game_id = ?`, id); err != nil {
+		return err
+	}
+	for i, e := range g.MoveLog().Entries {
+		if _, err := tx.Exec(`INSERT INTO moves (game_id, seq, from_bottle, to_bottle, amount, played_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, i, e.Move.From, e.Move.To, e.Move.Amount, e.Time); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadGame 按编号还原一局对局
+func (s *Store) LoadGame(id string) (*game.WaterBottleGame, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM games WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("没有编号为%q的对局", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return game.LoadGameFromData([]byte(data))
+}
+
+// ListGames 列出库里的对局编号, 最近更新的在前
+func (s *Store) ListGames() ([]string, error) {
+	rows, err := s.db.Query(`SELECT id FROM games ORDER BY updated_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteGame 删除一局对局及其走子记录
+func (s *Store) DeleteGame(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM moves WHERE game_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM games WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Stats 跨局统计: 库里有多少局, 一共走了多少步
+func (s *Store) Stats() (games, moves int, err error) {
+	if err = s.db.QueryRow(`SELECT COUNT(*) FROM games`).Scan(&games); err != nil {
+		return 0, 0, err
+	}
+	if err = s.db.QueryRow(`SELECT COUNT(*) FROM moves`).Scan(&moves); err != nil {
+		return 0, 0, err
+	}
+	return games, moves, nil
+}
+
+// Save 实现storage.Storage: 命名字节块写进blobs表
+func (s *Store) Save(name string, data []byte) error {
+	_, err := s.db.Exec(`INSERT INTO blobs (name, data) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET data=excluded.data`, name, data)
+	return err
+}
+
+// Load 实现storage.Storage
+func (s *Store) Load(name string) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM blobs WHERE name = ?`, name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("没有名为%q的数据", name)
+	}
+	return data, err
+}
+
+// List 实现storage.Lister
+func (s *Store) List() ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM blobs ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// EncryptedStorage 在底层Storage之上加一层AES-GCM加密.
+// 适合共用电脑的玩家, 防止存档和战绩被翻看或篡改.
+type EncryptedStorage struct {
+	inner Storage
+	key   []byte
+}
+
+// NewEncryptedStorage 用口令包装一个底层存储, 口令经SHA-256派生为密钥
+func NewEncryptedStorage(inner Storage, passphrase string) *EncryptedStorage {
+	key := sha256.Sum256([]byte(passphrase))
+	return &EncryptedStorage{inner: inner, key: key[:]}
+}
+
+// Save 加密后写入底层存储, 数据格式为 nonce||密文
+func (s *EncryptedStorage) Save(name string, data []byte) error {
+	gcm, err := s.gcm()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	sealed := gcm.Seal(nonce, nonce, data, nil)
+	return s.inner.Save(name, sealed)
+}
+
+// Load 从底层存储读取并解密, 口令不对或数据被篡改时返回错误
+func (s *EncryptedStorage) Load(name string) ([]byte, error) {
+	sealed, err := s.inner.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("存档数据不完整")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("解密失败: 口令错误或存档被篡改")
+	}
+	return plain, nil
+}
+
+// List 透传给底层存储(文件名不加密), 底层不支持列举时返回错误
+func (s *EncryptedStorage) List() ([]string, error) {
+	if l, ok := s.inner.(Lister); ok {
+		return l.List()
+	}
+	return nil, errors.New("底层存储不支持列举")
+}
+
+func (s *EncryptedStorage) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
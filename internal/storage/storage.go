@@ -0,0 +1,100 @@
+// Package storage 负责游戏存档和玩家档案的底层读写.
+// 所有持久化都经过Storage接口, 方便替换实现(明文文件/加密文件等).
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Storage 字节级的存取接口
+type Storage interface {
+	Save(name string, data []byte) error
+	Load(name string) ([]byte, error)
+}
+
+// Lister 可选扩展: 支持列出已保存的条目名
+type Lister interface {
+	List() ([]string, error)
+}
+
+// FileStorage 把数据保存为目录下的文件
+type FileStorage struct {
+	Dir string
+}
+
+// NewFileStorage 创建基于目录的存储
+func NewFileStorage(dir string) *FileStorage {
+	return &FileStorage{Dir: dir}
+}
+
+// Save 原子写入: 先写入临时文件并fsync落盘, 再整体改名替换.
+// 写到一半崩溃也不会损坏原有数据, 被替换的旧版本保留为.bak.
+func (s *FileStorage) Save(name string, data []byte) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, name)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Rename(path, path+".bak"); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load 读取内容. 主文件不存在(崩溃发生在替换途中)时退回.bak里的上一个
+// 版本; 残留的.tmp半截文件永远不会被读到.
+func (s *FileStorage) Load(name string) ([]byte, error) {
+	path := filepath.Join(s.Dir, name)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return data, nil
+	}
+	if os.IsNotExist(err) {
+		if bak, berr := os.ReadFile(path + ".bak"); berr == nil {
+			return bak, nil
+		}
+	}
+	return nil, err
+}
+
+// List 列出目录下已保存的条目名, .bak和.tmp等中间文件不算.
+// 目录还不存在时返回空列表.
+func (s *FileStorage) List() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) == ".bak" || filepath.Ext(name) == ".tmp" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,116 @@
+// Package tui 基于Bubble Tea的交互界面: 实时棋盘, 方向键/数字键选瓶,
+// 快捷键撤销和提示, 是演示程序行式命令循环之外的另一种玩法.
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iCode-five/hello-word/internal/game"
+)
+
+// Model TUI的状态: 对局, 光标所在的容器和已选定的源瓶
+type Model struct {
+	g       *game.WaterBottleGame
+	cursor  int    // 光标指向的容器编号
+	source  int    // 已选定的源瓶, -1表示还没选
+	message string // 最近一次操作的反馈
+}
+
+// New 创建TUI模型
+func New(g *game.WaterBottleGame) Model {
+	return Model{g: g, source: -1}
+}
+
+// Init 实现tea.Model
+func (m Model) Init() tea.Cmd { return nil }
+
+// Update 实现tea.Model: 处理按键
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	n := len(m.g.Bottles)
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "left":
+		m.cursor = (m.cursor + n - 1) % n
+	case "right":
+		m.cursor = (m.cursor + 1) % n
+	case "enter", " ":
+		m = m.pick(m.cursor)
+	case "u":
+		if m.g.Undo() {
+			m.message = "已撤销一步"
+		} else {
+			m.message = "没有可以撤销的操作"
+		}
+		m.source = -1
+	case "h":
+		if mv, ok := m.g.GetHint(); ok {
+			m.message = fmt.Sprintf("💡 建议: %s -> %s", m.g.ContainerName(mv.From), m.g.ContainerName(mv.To))
+		} else {
+			m.message = "💡 求解器没有找到建议"
+		}
+	default:
+		if len(key.String()) == 1 && key.String()[0] >= '0' && key.String()[0] <= '9' {
+			if i := int(key.String()[0] - '0'); i < n {
+				m.cursor = i
+				m = m.pick(i)
+			}
+		}
+	}
+	if m.g.Result() != game.ResultPlaying {
+		m.message = m.g.Result().Message()
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// pick 选定一个容器: 第一次选源瓶, 第二次执行倒水
+func (m Model) pick(i int) Model {
+	if m.source < 0 {
+		m.source = i
+		m.message = fmt.Sprintf("已选源瓶 %s, 再选目标", m.g.ContainerName(i))
+		return m
+	}
+	if m.source == i {
+		m.source = -1
+		m.message = "已取消选择"
+		return m
+	}
+	if _, err := m.g.Pour(m.source, i); err != nil {
+		m.message = "❌ " + m.g.GetPourFailureReason(m.source, i)
+	} else {
+		m.message = fmt.Sprintf("%s -> %s", m.g.ContainerName(m.source), m.g.ContainerName(i))
+	}
+	m.source = -1
+	return m
+}
+
+// View 实现tea.Model: 棋盘加状态栏
+func (m Model) View() string {
+	var b strings.Builder
+	opts := game.DefaultRenderOptions()
+	opts.Columnar = true
+	m.g.Render(&b, opts)
+	b.WriteString("\n")
+	if m.source >= 0 {
+		fmt.Fprintf(&b, "源瓶: %s  ", m.g.ContainerName(m.source))
+	}
+	fmt.Fprintf(&b, "光标: %s\n", m.g.ContainerName(m.cursor))
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	b.WriteString("←/→或数字选瓶  回车确认  u撤销  h提示  q退出\n")
+	return b.String()
+}
+
+// Run 启动TUI, 对局结束或玩家退出时返回
+func Run(g *game.WaterBottleGame) error {
+	_, err := tea.NewProgram(New(g)).Run()
+	return err
+}
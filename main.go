@@ -0,0 +1,1401 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iCode-five/hello-word/internal/campaign"
+	"github.com/iCode-five/hello-word/internal/game"
+	"github.com/iCode-five/hello-word/internal/pack"
+	"github.com/iCode-five/hello-word/internal/profile"
+	"github.com/iCode-five/hello-word/internal/server"
+	"github.com/iCode-five/hello-word/internal/session"
+	"github.com/iCode-five/hello-word/internal/sqlstore"
+	"github.com/iCode-five/hello-word/internal/storage"
+	"github.com/iCode-five/hello-word/internal/tui"
+)
+
+// 非交互模式的命令行参数; --bottles 大于0时跳过交互式提问
+var (
+	flagBottles  = flag.Int("bottles", 0, "瓶子数量 (>0时启用非交互模式)")
+	flagCapacity = flag.Int("capacity", 4, "瓶子容量")
+	flagColors   = flag.Int("colors", 4, "颜色数量")
+	flagEmpty    = flag.Int("empty", 2, "空瓶数量")
+	flagJars     = flag.Int("jars", 0, "罐子数量")
+	flagJarCap   = flag.Int("jarcap", 6, "罐子容量")
+	flagBags     = flag.Bool("bags", false, "开启袋子模式")
+	flagStrict   = flag.Bool("strict", false, "开启严格模式")
+	flagGen      = flag.String("gen", "reverse", "生成方式: reverse 或 random")
+	flagSteps    = flag.Int("steps", 0, "反向生成步数, 0表示自动")
+	flagSeed     = flag.Int64("seed", 0, "随机种子, 0表示按当前时间")
+	flagScript   = flag.String("script", "", "走子脚本文件, 每行一条: <源瓶> <目标瓶>")
+	flagDebug    = flag.Bool("debug", false, "调试模式, 解锁作弊命令")
+	flagCampaign = flag.Bool("campaign", false, "进入主线战役模式")
+	flagRules    = flag.String("rules", "water", "规则变体: water(倒水) 或 ball(球排序)")
+	flagWild     = flag.Int("wild", 0, "万能色(🌈)格数, 0表示不用")
+	flagFrozen   = flag.Int("frozen", 0, "冰冻(🧊)格数, 0表示不用")
+	flagBombs    = flag.Int("bombs", 0, "炸弹(💣)格数, 0表示不用")
+	flagFuse     = flag.Int("fuse", 20, "炸弹引信步数")
+	flagEndless  = flag.Int("endless", 0, "生存模式: 每N步来一个新瓶, 0表示关闭")
+	flagGoals    = flag.String("goals", "", "关卡目标, 逗号分隔: nojar / noundo / moves=N / first=颜色编号")
+	flagMoveCap  = flag.Int("movelimit", 0, "步数上限, 0表示不限")
+	flagTimeCap  = flag.Duration("timelimit", 0, "倒计时时长(如90s/2m), 0表示不限")
+	flagBatch    = flag.Int("batch", 0, "批量生成谜题数 (>0时进入批量生成模式)")
+	flagOut      = flag.String("out", "levels", "批量生成的输出目录")
+	flagDaily    = flag.Bool("daily", false, "挑战今天的每日谜题")
+	flagTutorial = flag.String("tutorial", "", "进入教学关: jar(罐子) 或 bag(袋子)")
+	flagLang     = flag.String("lang", "zh-CN", "引擎文案语言: zh-CN 或 en-US")
+	flagStyle    = flag.String("style", "emoji", "水层显示样式: emoji / letters(色盲友好字母) / patterns(纹理)")
+	flagTUI      = flag.Bool("tui", false, "用全屏TUI界面代替行式命令循环")
+	flagEconomy  = flag.Bool("economy", false, "开启经济模式: 提示/洗牌/加空瓶收费, 通关按难度发金币")
+	flagPowerups = flag.Bool("powerups", false, "开局发放标准道具组(换顶/抽水/窥视/回溯)")
+	flagServe    = flag.String("serve", "", "启动REST服务器, 值为监听地址如 :8080")
+	flagAPIKeys  = flag.String("apikeys", "", "服务器的API key列表, 逗号分隔, 空表示不鉴权")
+	flagRate     = flag.Int("ratelimit", 0, "服务器每个调用方每分钟的请求配额, 0表示不限流")
+	flagPprof    = flag.Bool("pprof", false, "服务器开启/debug/pprof诊断端点")
+	flagDB       = flag.String("db", "", "服务器的SQLite数据库文件, 对局落库后重启不丢")
+	flagGenRetry = flag.Int("genretries", 0, "生成验证失败的重试上限, 0取默认值10")
+	flagCalib    = flag.Int("calibrate", 0, "难度校准模式: 自玩N道谜题拟合难度区间, 0表示关闭")
+	flagDataset  = flag.Int("dataset", 0, "训练数据模式: 生成并求解N道谜题导出(局面,走子)样本, 0表示关闭")
+)
+
+// displayStyle 解析好的--style值, 每局开局时应用
+var displayStyle game.DisplayStyle
+
+// parseStyle 解析--style参数
+func parseStyle(s string) (game.DisplayStyle, error) {
+	switch s {
+	case "emoji":
+		return game.StyleEmoji, nil
+	case "letters":
+		return game.StyleLetters, nil
+	case "patterns":
+		return game.StylePatterns, nil
+	default:
+		return 0, fmt.Errorf("未知的显示样式: %s", s)
+	}
+}
+
+// parseRules 解析--rules参数
+func parseRules(s string) (game.RuleSet, error) {
+	switch s {
+	case "water":
+		return game.RuleWaterSort, nil
+	case "ball":
+		return game.RuleBallSort, nil
+	default:
+		return 0, fmt.Errorf("未知的规则变体: %s", s)
+	}
+}
+
+func main() {
+	flag.Parse()
+	if err := game.SetLanguage(*flagLang); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var err error
+	if displayStyle, err = parseStyle(*flagStyle); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *flagServe != "" {
+		if err := runServer(*flagServe); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagCampaign {
+		if err := runCampaign(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagDataset > 0 {
+		if err := runDatasetExport(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagCalib > 0 {
+		if err := runCalibrate(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagBatch > 0 {
+		if err := runBatchGenerate(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagTUI {
+		if err := runTUI(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagDaily {
+		runDaily()
+		return
+	}
+	if *flagTutorial != "" {
+		if err := runTutorial(*flagTutorial); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *flagBottles > 0 {
+		if err := runNonInteractive(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	runWaterBottleDemo()
+}
+
+// runNonInteractive 按命令行参数直接建局, 可选地执行脚本后打印结果
+func runNonInteractive() error {
+	seed := *flagSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rules, err := parseRules(*flagRules)
+	if err != nil {
+		return err
+	}
+	if v := game.ValidateParams(*flagBottles, *flagCapacity, *flagColors, *flagEmpty, *flagJars, *flagJarCap); !v.OK {
+		for _, issue := range v.Issues {
+			fmt.Fprintf(os.Stderr, "参数错误: %s\n", issue.Message)
+		}
+		for _, s := range v.Suggestions {
+			fmt.Fprintf(os.Stderr, "建议: %s\n", s.Note)
+		}
+		return fmt.Errorf("参数不合法")
+	}
+	g := game.NewWaterBottleGameWithSeed(*flagBottles, *flagCapacity, *flagColors,
+		*flagEmpty, *flagJars, *flagJarCap, *flagBags, seed)
+	g.SetRuleSet(rules)
+	g.SetStrictMode(*flagStrict)
+	g.SetDisplayStyle(displayStyle)
+	applyEconomy(g)
+	if err := applyGoals(g); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if *flagPowerups {
+		g.GrantDefaultPowerUps()
+	}
+	g.SetGenerationRetries(*flagGenRetry)
+	switch *flagGen {
+	case "reverse":
+		if *flagSteps > 0 {
+			g.GenerateByReverse(*flagSteps)
+		}
+	case "random":
+		if err := g.GenerateRandomSolvable(20); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("未知的生成方式: %s", *flagGen)
+	}
+	if r := g.LastGenerationReport(); r.Attempts > 0 {
+		fmt.Printf("📊 生成报告: 尝试%d次, 成功率%.0f%%, 打乱%d/%d步, 耗时%s\n",
+			r.Attempts, r.SuccessRate*100, r.ActualSteps, r.RequestedSteps,
+			r.Elapsed.Round(time.Millisecond))
+	}
+	if *flagWild > 0 {
+		g.InjectWildcards(*flagWild)
+	}
+	if *flagFrozen > 0 {
+		g.InjectFrozen(*flagFrozen)
+	}
+	if *flagBombs > 0 {
+		g.InjectBombs(*flagBombs, *flagFuse)
+	}
+	if *flagEndless > 0 {
+		g.SetEndlessMode(*flagEndless, 2*len(g.Bottles))
+	}
+	g.SetMoveLimit(*flagMoveCap)
+	if *flagTimeCap > 0 {
+		g.SetTimeLimit(*flagTimeCap)
+	}
+	fmt.Printf("种子: %d  难度: %s\n", g.Seed(), g.Difficulty())
+	if *flagScript != "" {
+		if err := applyScript(g, *flagScript); err != nil {
+			return err
+		}
+	}
+	g.PrintState()
+	fmt.Println(g.Result().Message())
+	return nil
+}
+
+// runTUI 全屏TUI模式: 参数沿用非交互模式的flag, --bottles没给时
+// 用常规的6瓶4色配置
+func runTUI() error {
+	bottles := *flagBottles
+	if bottles <= 0 {
+		bottles = 6
+	}
+	seed := *flagSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g := game.NewWaterBottleGameWithSeed(bottles, *flagCapacity, *flagColors,
+		*flagEmpty, *flagJars, *flagJarCap, *flagBags, seed)
+	g.SetDisplayStyle(displayStyle)
+	return tui.Run(g)
+}
+
+// runDatasetExport 训练数据模式: 按命令行的容器参数生成并求解谜题,
+// 把解路径上的(局面,最优走子)样本写成JSONL文件. --out是输出文件名,
+// 还用批量模式的默认值"levels"时改写成dataset.jsonl.
+func runDatasetExport() error {
+	out := *flagOut
+	if out == "levels" {
+		out = "dataset.jsonl"
+	}
+	bottles := *flagBottles
+	if bottles <= 0 {
+		bottles = 6
+	}
+	cfg := game.GameConfig{
+		BottleCount:  bottles,
+		Capacity:     *flagCapacity,
+		ColorCount:   *flagColors,
+		EmptyBottles: *flagEmpty,
+		JarCount:     *flagJars,
+		JarCapacity:  *flagJarCap,
+		UseBags:      *flagBags,
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Printf("📚 训练数据导出: %d瓶%d色, 生成并求解%d道谜题...\n", bottles, *flagColors, *flagDataset)
+	stats, err := game.ExportDataset(f, cfg, *flagDataset, *flagSeed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ 导出%d条样本 (谜题%d道, 未解出%d道, 去重%d条, 耗时%s), 已写入 %s\n",
+		stats.Samples, stats.Puzzles, stats.Unsolved, stats.Deduped,
+		stats.Elapsed.Round(time.Millisecond), out)
+	return nil
+}
+
+// runCalibrate 难度校准模式: 按命令行的容器参数自玩N道谜题,
+// 打印各最短解步数上的贪心通关率和拟合出的难度区间
+func runCalibrate() error {
+	bottles := *flagBottles
+	if bottles <= 0 {
+		bottles = 6
+	}
+	params := game.CalibrationParams{
+		Config: game.GameConfig{
+			BottleCount:  bottles,
+			Capacity:     *flagCapacity,
+			ColorCount:   *flagColors,
+			EmptyBottles: *flagEmpty,
+			JarCount:     *flagJars,
+			JarCapacity:  *flagJarCap,
+			UseBags:      *flagBags,
+		},
+		Samples: *flagCalib,
+		Seed:    *flagSeed,
+	}
+	fmt.Printf("🔬 难度校准: %d瓶%d色, 自玩%d道谜题...\n", bottles, *flagColors, *flagCalib)
+	res, err := game.CalibrateDifficulty(params)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("样本%d道 (求解器未解出%d道), 总体贪心通关率%.0f%%, 耗时%s\n",
+		res.Samples, res.Unsolved, res.GreedyRate*100, res.Elapsed.Round(time.Millisecond))
+	var lengths []int
+	for l := range res.RateByMoves {
+		lengths = append(lengths, l)
+	}
+	sort.Ints(lengths)
+	for _, l := range lengths {
+		fmt.Printf("  最短%2d步: 贪心通关率%.0f%%\n", l, res.RateByMoves[l]*100)
+	}
+	fmt.Printf("拟合区间: %s\n", res.Bands)
+	return nil
+}
+
+// runServer REST服务器模式: 在指定地址上提供建局/倒水/求解接口,
+// /metrics输出Prometheus格式的运行指标
+func runServer(addr string) error {
+	srv := server.New()
+	if *flagAPIKeys != "" {
+		srv.SetAPIKeys(strings.Split(*flagAPIKeys, ","))
+		fmt.Println("🔑 已开启API key鉴权")
+	}
+	if *flagRate > 0 {
+		srv.SetRateLimit(*flagRate)
+		fmt.Printf("🚦 限流: 每个调用方每分钟%d次请求\n", *flagRate)
+	}
+	if *flagPprof {
+		srv.EnablePprof()
+		fmt.Println("🔍 已开启/debug/pprof诊断端点")
+	}
+	if *flagDB != "" {
+		store, err := sqlstore.Open(*flagDB)
+		if err != nil {
+			return fmt.Errorf("打开数据库失败: %w", err)
+		}
+		defer store.Close()
+		restored, err := srv.SetStore(store)
+		if err != nil {
+			return fmt.Errorf("恢复历史对局失败: %w", err)
+		}
+		fmt.Printf("💾 对局持久化到 %s, 恢复了%d局\n", *flagDB, restored)
+	}
+	fmt.Printf("🌐 REST服务器监听 %s (指标见 /metrics)\n", addr)
+	return http.ListenAndServe(addr, srv.Handler())
+}
+
+// runTutorial 教学关模式: 按脚本一步一步带玩家过规则
+func runTutorial(kind string) error {
+	var g *game.WaterBottleGame
+	var err error
+	switch kind {
+	case "jar":
+		g, err = game.NewJarTutorial()
+	case "bag":
+		g, err = game.NewBagTutorial()
+	default:
+		return fmt.Errorf("未知的教学关: %s (可选 jar 或 bag)", kind)
+	}
+	if err != nil {
+		return err
+	}
+	g.SetDisplayStyle(displayStyle)
+	g.SetLogger(game.StdoutLogger)
+	playGame(g, bufio.NewScanner(os.Stdin))
+	return nil
+}
+
+// runDaily 每日谜题模式: 种子由UTC日期确定, 全球玩家同一天拿到
+// 同一局. 通关后更新本地连胜记录.
+func runDaily() {
+	today := time.Now()
+	g := game.NewDailyPuzzle(today, 6, 4, 4, 2, 0, 0, false)
+	g.SetDebug(*flagDebug)
+	g.SetDisplayStyle(displayStyle)
+	g.SetLogger(game.StdoutLogger)
+	applyEconomy(g)
+	if err := applyGoals(g); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if *flagPowerups {
+		g.GrantDefaultPowerUps()
+	}
+	fmt.Printf("📅 每日谜题 %s  难度: %s\n", today.UTC().Format("2006-01-02"), g.Difficulty())
+	scanner := bufio.NewScanner(os.Stdin)
+	code := playGame(g, scanner)
+	recordProfile(g, code.IsWin())
+	if !code.IsWin() {
+		return
+	}
+	store := storage.NewFileStorage(defaultSaveDir())
+	s, err := profile.LoadStreak(store)
+	if err != nil {
+		fmt.Printf("⚠️ 读取连胜记录失败: %v\n", err)
+		return
+	}
+	s.RecordWin(today)
+	if err := s.Save(store); err != nil {
+		fmt.Printf("⚠️ 保存连胜记录失败: %v\n", err)
+		return
+	}
+	fmt.Printf("🔥 每日连胜: %d天 (最佳%d天)\n", s.Current, s.Best)
+}
+
+// runBatchGenerate 批量生成模式: 按命令行参数量产谜题, 用规范键去重,
+// 带上难度标签写成谜题包格式. 参数沿用非交互模式的flag, --bottles
+// 没给时用常规的6瓶4色配置.
+func runBatchGenerate() error {
+	bottles, capacity, colors, empty := *flagBottles, *flagCapacity, *flagColors, *flagEmpty
+	if bottles <= 0 {
+		bottles = 6
+	}
+	if v := game.ValidateParams(bottles, capacity, colors, empty, *flagJars, *flagJarCap); !v.OK {
+		for _, issue := range v.Issues {
+			fmt.Fprintf(os.Stderr, "参数错误: %s\n", issue.Message)
+		}
+		return fmt.Errorf("参数不合法")
+	}
+	baseSeed := *flagSeed
+	if baseSeed == 0 {
+		baseSeed = time.Now().UnixNano()
+	}
+	p := &pack.Pack{Name: fmt.Sprintf("批量生成_%d", baseSeed)}
+	seen := make(map[string]bool)
+	attempts := 0
+	for len(p.Levels) < *flagBatch {
+		attempts++
+		if attempts > *flagBatch*10 {
+			return fmt.Errorf("尝试%d次后仍凑不够%d个不重复的谜题", attempts, *flagBatch)
+		}
+		g := game.NewWaterBottleGameWithSeed(bottles, capacity, colors, empty,
+			*flagJars, *flagJarCap, *flagBags, baseSeed+int64(attempts))
+		key := g.CanonicalKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		p.Levels = append(p.Levels, pack.FromGame(fmt.Sprintf("谜题%04d", len(p.Levels)+1), g))
+		if len(p.Levels)%100 == 0 {
+			fmt.Printf("  已生成 %d/%d\n", len(p.Levels), *flagBatch)
+		}
+	}
+	if err := p.Export(*flagOut); err != nil {
+		return err
+	}
+	fmt.Printf("✅ 生成%d个谜题(尝试%d次, 去重%d个), 已写入 %s\n",
+		len(p.Levels), attempts, attempts-len(p.Levels), *flagOut)
+	return nil
+}
+
+// applyScript 逐行执行脚本里的倒水命令, 空行和#开头的行忽略
+func applyScript(g *game.WaterBottleGame, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for ln, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		// 记谱形式的行(如 B0>B3x2)整行是一条走子
+		if len(fields) == 1 && strings.Contains(fields[0], ">") {
+			if _, err := g.PlayNotation(fields[0]); err != nil {
+				return fmt.Errorf("脚本第%d行走子失败: %w", ln+1, err)
+			}
+			continue
+		}
+		if len(fields) != 2 {
+			return fmt.Errorf("脚本第%d行格式不对: %q", ln+1, line)
+		}
+		from, err1 := strconv.Atoi(fields[0])
+		to, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("脚本第%d行瓶子编号不是数字: %q", ln+1, line)
+		}
+		if _, err := g.Pour(from, to); err != nil {
+			return fmt.Errorf("脚本第%d行倒水失败 (%d->%d): %w", ln+1, from, to, err)
+		}
+		fmt.Printf("脚本: %s -> %s\n", g.ContainerName(from), g.ContainerName(to))
+	}
+	return nil
+}
+
+// readInt 从输入读取一个整数, 读取失败时使用默认值
+func readInt(scanner *bufio.Scanner, prompt string, def int) int {
+	fmt.Printf("%s (默认%d): ", prompt, def)
+	if !scanner.Scan() {
+		return def
+	}
+	text := strings.TrimSpace(scanner.Text())
+	if text == "" {
+		return def
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		fmt.Println("输入无效, 使用默认值")
+		return def
+	}
+	return n
+}
+
+// runWaterBottleDemo 交互式演示入口
+func runWaterBottleDemo() {
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Println("=== 倒水排序游戏 ===")
+	bottleCount := readInt(scanner, "瓶子数量", 6)
+	capacity := readInt(scanner, "瓶子容量", 4)
+	colorCount := readInt(scanner, "颜色数量", 4)
+	emptyBottles := readInt(scanner, "空瓶数量", 2)
+	jarCount := readInt(scanner, "罐子数量", 0)
+	jarCapacity := 0
+	if jarCount > 0 {
+		jarCapacity = readInt(scanner, "罐子容量", 6)
+	}
+	fmt.Print("开启袋子模式? (y/n, 默认n): ")
+	useBags := false
+	if scanner.Scan() && strings.TrimSpace(scanner.Text()) == "y" {
+		useBags = true
+	}
+	fmt.Print("球排序变体(一次只移动一颗)? (y/n, 默认n): ")
+	rules := game.RuleWaterSort
+	if scanner.Scan() && strings.TrimSpace(scanner.Text()) == "y" {
+		rules = game.RuleBallSort
+	}
+	fmt.Print("开启严格模式(不能拆开已完成的瓶子)? (y/n, 默认n): ")
+	strict := false
+	if scanner.Scan() && strings.TrimSpace(scanner.Text()) == "y" {
+		strict = true
+	}
+	fmt.Print("隐藏层模式(只能看到顶层)? (y/n, 默认n): ")
+	mystery := false
+	if scanner.Scan() && strings.TrimSpace(scanner.Text()) == "y" {
+		mystery = true
+	}
+	wild := readInt(scanner, "万能色(🌈)格数, 0表示不用", 0)
+	frozen := readInt(scanner, "冰冻(🧊)格数, 0表示不用", 0)
+	bombs := readInt(scanner, "炸弹(💣)格数, 0表示不用", 0)
+	bombFuse := 0
+	if bombs > 0 {
+		bombFuse = readInt(scanner, "炸弹引信步数", 20)
+	}
+	moveLimit := readInt(scanner, "步数上限, 0表示不限", 0)
+	timeLimitSec := readInt(scanner, "倒计时秒数, 0表示不限", 0)
+
+	// 先过参数校验, 有问题时展示原因并采纳第一条建议
+	if v := game.ValidateParams(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity); !v.OK {
+		for _, issue := range v.Issues {
+			fmt.Printf("⚠️ %s\n", issue.Message)
+		}
+		if len(v.Suggestions) > 0 {
+			s := v.Suggestions[0]
+			fmt.Printf("已自动调整: %s\n", s.Note)
+			bottleCount, capacity, colorCount = s.BottleCount, s.Capacity, s.ColorCount
+			emptyBottles, jarCount, jarCapacity = s.EmptyBottles, s.JarCount, s.JarCapacity
+		}
+	}
+
+	// 大盘的反向生成可能要跑好几秒: 先展示临时的已解好局面,
+	// 真谜题在后台生成, 就绪后换入, 演示程序不会显得冻结.
+	var g *game.WaterBottleGame
+	if bottleCount*capacity*colorCount > 400 {
+		g = game.NewWaterBottleGameLazy(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags)
+		g.SetRuleSet(rules)
+		fmt.Println("⏳ 棋盘较大, 谜题在后台生成中, 先展示临时局面...")
+		g.PrintState()
+		for up := range g.GenerateInBackground() {
+			if up.Result != nil {
+				g.SwapGenerated(up.Result)
+				fmt.Println("✅ 谜题就绪!")
+			} else {
+				fmt.Printf("  生成中... %d%%\n", up.Percent)
+			}
+		}
+	} else {
+		g = game.NewWaterBottleGame(bottleCount, capacity, colorCount, emptyBottles, jarCount, jarCapacity, useBags)
+		g.SetRuleSet(rules)
+	}
+	g.SetStrictMode(strict)
+	g.SetMysteryMode(mystery)
+	if wild > 0 {
+		g.InjectWildcards(wild)
+	}
+	if frozen > 0 {
+		g.InjectFrozen(frozen)
+	}
+	if bombs > 0 {
+		g.InjectBombs(bombs, bombFuse)
+	}
+	if *flagEndless > 0 {
+		g.SetEndlessMode(*flagEndless, 2*len(g.Bottles))
+	}
+	g.SetMoveLimit(moveLimit)
+	if timeLimitSec > 0 {
+		g.SetTimeLimit(time.Duration(timeLimitSec) * time.Second)
+	}
+	g.SetDebug(*flagDebug)
+	g.SetDisplayStyle(displayStyle)
+	g.SetLogger(game.StdoutLogger)
+	applyEconomy(g)
+	if err := applyGoals(g); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if *flagPowerups {
+		g.GrantDefaultPowerUps()
+	}
+	fmt.Printf("难度: %s\n", g.Difficulty())
+	code := playGame(g, scanner)
+	recordProfile(g, code.IsWin())
+}
+
+// applyGoals 解析--goals并给本局设置目标清单
+func applyGoals(g *game.WaterBottleGame) error {
+	if *flagGoals == "" {
+		return nil
+	}
+	var objs []game.Objective
+	for _, part := range strings.Split(*flagGoals, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "nojar":
+			objs = append(objs, game.ObjectiveNoJar())
+		case part == "noundo":
+			objs = append(objs, game.ObjectiveNoUndo())
+		case strings.HasPrefix(part, "moves="):
+			n, err := strconv.Atoi(part[len("moves="):])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("目标%q的步数不合法", part)
+			}
+			objs = append(objs, game.ObjectiveMaxMoves(n))
+		case strings.HasPrefix(part, "first="):
+			c, err := strconv.Atoi(part[len("first="):])
+			if err != nil || c < 1 {
+				return fmt.Errorf("目标%q的颜色编号不合法", part)
+			}
+			objs = append(objs, game.ObjectiveCompleteColorFirst(c))
+		default:
+			return fmt.Errorf("未知的目标: %q", part)
+		}
+	}
+	g.SetObjectives(objs...)
+	return nil
+}
+
+// printObjectives 打印目标清单的达成状态
+func printObjectives(g *game.WaterBottleGame) {
+	for _, r := range g.CheckObjectives() {
+		mark := "☐"
+		if r.Done {
+			mark = "☑"
+		}
+		fmt.Printf("%s %s\n", mark, r.Description)
+	}
+}
+
+// applyEconomy 按--economy开关启用经济模式, 并把档案里的金币余额带入本局
+func applyEconomy(g *game.WaterBottleGame) {
+	if !*flagEconomy {
+		return
+	}
+	g.SetEconomyMode(true)
+	store := storage.NewFileStorage(defaultSaveDir())
+	p, err := profile.Load(store)
+	if err != nil {
+		fmt.Printf("⚠️ 读取档案失败: %v\n", err)
+		return
+	}
+	g.AddCoins(p.Coins)
+	fmt.Printf("💰 经济模式已开启, 余额%d金币\n", g.Coins())
+}
+
+// recordProfile 把一局的统计累加进本地玩家档案并打印简报
+func recordProfile(g *game.WaterBottleGame, won bool) {
+	store := storage.NewFileStorage(defaultSaveDir())
+	p, err := profile.Load(store)
+	if err != nil {
+		fmt.Printf("⚠️ 读取档案失败: %v\n", err)
+		return
+	}
+	p.Record(g.GetStats(), won)
+	if g.EconomyMode() {
+		p.Coins = g.Coins()
+	}
+	if err := p.Save(store); err != nil {
+		fmt.Printf("⚠️ 保存档案失败: %v\n", err)
+		return
+	}
+	fmt.Printf("档案: 共%d局, 胜率%.0f%%, 平均%.1f步\n", p.GamesPlayed, p.WinRate()*100, p.AverageMoves())
+}
+
+// playGame 交互式对局的主循环, 返回对局结束时的结果;
+// 玩家中途退出时返回ResultPlaying.
+func playGame(g *game.WaterBottleGame, scanner *bufio.Scanner) game.ResultCode {
+	for {
+		g.PrintState()
+		if left := g.MovesLeft(); left >= 0 {
+			fmt.Printf("🚫 剩余步数: %d\n", left)
+		}
+		if left, ok := g.TimeLeft(); ok {
+			fmt.Printf("⏰ 剩余时间: %v\n", left.Round(time.Second))
+		}
+		if s := g.Score(); s > 0 {
+			if c := g.Combo(); c > 1 {
+				fmt.Printf("🏆 分数: %d (连击x%d)\n", s, c)
+			} else {
+				fmt.Printf("🏆 分数: %d\n", s)
+			}
+		}
+		if g.EconomyMode() {
+			fmt.Printf("💰 金币: %d\n", g.Coins())
+		}
+		if fuse := g.BombFuse(); fuse > 0 {
+			fmt.Printf("💣 引信: 还剩%d步\n", fuse)
+		}
+		if g.EndlessMode() {
+			fmt.Printf("♾️ 生存模式: 已完成%d瓶\n", g.GetStats().Completed)
+		}
+		if step, ok := g.CurrentTutorialStep(); ok {
+			fmt.Printf("🎓 %s\n", step.Message)
+			if !step.Free {
+				fmt.Printf("   高亮: %s -> %s\n", g.ContainerName(step.Move.From), g.ContainerName(step.Move.To))
+			}
+		}
+		if code := g.Result(); code != game.ResultPlaying {
+			fmt.Println(code.Message())
+			if code.IsWin() {
+				fmt.Printf("评分: %s (%d步, 标准%d步)\n",
+					strings.Repeat("⭐", g.RateSolution(g.MoveCount)), g.MoveCount, g.Par())
+			}
+			printObjectives(g)
+			return code
+		}
+		fmt.Print("\n输入命令 (倒水 a b / 提示 / 可行 / 撤销 / 难度 / 洗牌 / 加瓶 / 求解 / 退出): ")
+		if !scanner.Scan() {
+			return game.ResultPlaying
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		// 记谱形式的输入(如 B0>B3x2)直接当一条走子
+		if len(fields) == 1 && strings.Contains(fields[0], ">") {
+			if _, err := g.PlayNotation(fields[0]); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+			continue
+		}
+		switch fields[0] {
+		case "退出", "q":
+			return game.ResultPlaying
+		case "提示":
+			if m, ok := g.GetHint(); ok {
+				fmt.Printf("💡 建议: %s -> %s\n", g.ContainerName(m.From), g.ContainerName(m.To))
+			} else {
+				fmt.Println("💡 求解器没有找到建议")
+			}
+		case "蒙卡":
+			budget := 500 * time.Millisecond
+			if len(fields) == 2 {
+				ms, err := strconv.Atoi(fields[1])
+				if err != nil || ms <= 0 {
+					fmt.Println("用法: 蒙卡 [预算毫秒数]")
+					continue
+				}
+				budget = time.Duration(ms) * time.Millisecond
+			}
+			if a, ok := g.MCTSAdvise(budget); ok {
+				fmt.Printf("🎲 蒙特卡洛建议: %s -> %s (胜率估计%.0f%%, 探索%d次/共%d次迭代)\n",
+					g.ContainerName(a.Move.From), g.ContainerName(a.Move.To),
+					a.WinRate*100, a.Visits, a.Iterations)
+			} else {
+				fmt.Println("🎲 蒙特卡洛顾问没有找到建议")
+			}
+		case "可行":
+			for _, desc := range g.CheckPossibleMoves() {
+				fmt.Println("  " + desc)
+			}
+		case "撤销":
+			if !g.Undo() {
+				fmt.Println("没有可以撤销的操作")
+			}
+		case "重做":
+			if !g.Redo() {
+				fmt.Println("没有可以重做的操作")
+			}
+		case "重开":
+			if !g.Restart() {
+				fmt.Println("这局没有记录初始布局, 无法重开")
+			}
+		case "回退":
+			if g.UndoToLastCheckpoint() {
+				fmt.Println("⏪ 已回退到最近的可解局面")
+			} else {
+				fmt.Println("还没有已确认可解的检查点 (先用一次提示)")
+			}
+		case "沙盒":
+			if err := g.BeginSandbox(); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Println("🧪 已进入沙盒, 用\"沙盒提交\"保留或\"沙盒放弃\"回滚")
+			}
+		case "沙盒提交":
+			if err := g.CommitSandbox(); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Println("🧪 沙盒已提交")
+			}
+		case "沙盒放弃":
+			if err := g.DiscardSandbox(); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Println("🧪 已回滚到进沙盒时的局面")
+			}
+		case "打点":
+			id := g.Checkpoint()
+			fmt.Printf("📌 检查点%d已记录, 用\"跳转 %d\"随时回到这里\n", id, id)
+		case "跳转":
+			if len(fields) != 2 {
+				fmt.Println("用法: 跳转 <检查点编号>")
+				continue
+			}
+			id, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("检查点编号必须是数字")
+				continue
+			}
+			if err := g.JumpTo(id); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		case "分支":
+			cps := g.Checkpoints()
+			if len(cps) == 0 {
+				fmt.Println("还没有检查点 (用\"打点\"记录当前局面)")
+				continue
+			}
+			for _, cp := range cps {
+				mark := " "
+				if cp.Current {
+					mark = "*"
+				}
+				src := "开局"
+				if cp.Parent > 0 {
+					src = fmt.Sprintf("检查点%d", cp.Parent)
+				}
+				fmt.Printf("%s 检查点%d (第%d步, 源自%s)\n", mark, cp.ID, cp.MoveCount, src)
+			}
+		case "解数":
+			limit := 100
+			if len(fields) == 2 {
+				if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+					limit = n
+				}
+			}
+			n := g.CountSolutions(limit)
+			if n >= limit {
+				fmt.Printf("🔢 解法路径不少于%d条 (数到上限)\n", n)
+			} else {
+				fmt.Printf("🔢 共%d条不同的解法路径\n", n)
+			}
+		case "难度":
+			r := g.EstimateDifficulty()
+			if r.Solvable {
+				fmt.Printf("难度: %s (最短%d步, 平均分支%.1f, 死局%d个)\n", r.Level, r.OptimalMoves, r.BranchingFactor, r.DeadEnds)
+			} else {
+				fmt.Printf("难度: %s (求解器在上限内未找到解)\n", r.Level)
+			}
+		case "目标":
+			if len(g.Objectives()) == 0 {
+				fmt.Println("本局没有设置目标")
+				continue
+			}
+			printObjectives(g)
+		case "道具":
+			if len(fields) == 1 {
+				slots := g.PowerUps()
+				if len(slots) == 0 {
+					fmt.Println("道具栏是空的 (用--powerups开局发放标准道具组)")
+					continue
+				}
+				for _, s := range slots {
+					fmt.Printf("✨ %s x%d: %s\n", s.Item.Name(), s.Uses, s.Item.Description())
+				}
+				continue
+			}
+			var targets []int
+			bad := false
+			for _, f := range fields[2:] {
+				n, err := strconv.Atoi(f)
+				if err != nil {
+					fmt.Println("道具目标必须是容器编号")
+					bad = true
+					break
+				}
+				targets = append(targets, n)
+			}
+			if bad {
+				continue
+			}
+			if err := g.UsePowerUp(fields[1], targets...); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		case "洗牌":
+			g.ShuffleWater()
+		case "加瓶":
+			g.AddEmptyBottle()
+		case "罐型":
+			if len(fields) != 3 {
+				fmt.Println("用法: 罐型 <容器编号> <普通|缓冲|只进|封印>")
+				continue
+			}
+			idx, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("容器编号必须是数字")
+				continue
+			}
+			kinds := map[string]game.JarKind{
+				"普通": game.JarNormal, "缓冲": game.JarBuffer,
+				"只进": game.JarReceiveOnly, "封印": game.JarSealed,
+			}
+			kind, ok := kinds[fields[2]]
+			if !ok {
+				fmt.Println("罐子变体可选: 普通 / 缓冲 / 只进 / 封印")
+				continue
+			}
+			if err := g.SetJarKind(idx, kind); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				fmt.Printf("🏺 %s已配置为%s\n", g.ContainerName(idx), kind)
+			}
+		case "加罐":
+			capacity := 0
+			if len(fields) == 2 {
+				n, err := strconv.Atoi(fields[1])
+				if err != nil {
+					fmt.Println("容量必须是数字")
+					continue
+				}
+				capacity = n
+			}
+			g.AddEmptyJar(capacity)
+		case "求解":
+			strategy := ""
+			if len(fields) == 2 {
+				strategy = fields[1]
+			}
+			demonstrateBasicSolver(g, strategy)
+		case "自动求解":
+			autoSolvePlayback(g)
+		case "点评":
+			if len(fields) != 3 {
+				fmt.Println("用法: 点评 <源瓶> <目标瓶>")
+				continue
+			}
+			from, err1 := strconv.Atoi(fields[1])
+			to, err2 := strconv.Atoi(fields[2])
+			if err1 != nil || err2 != nil {
+				fmt.Println("瓶子编号必须是数字")
+				continue
+			}
+			ev := g.EvaluateMove(game.Move{From: from, To: to})
+			if ev.Legal {
+				fmt.Printf("📝 %s -> %s 评分%+d\n", g.ContainerName(from), g.ContainerName(to), ev.Score)
+			} else {
+				fmt.Printf("📝 %s -> %s 不可行\n", g.ContainerName(from), g.ContainerName(to))
+			}
+			for _, r := range ev.Reasons {
+				fmt.Println("  · " + r)
+			}
+		case "保存":
+			if len(fields) != 2 {
+				fmt.Println("用法: 保存 <存档名>")
+				continue
+			}
+			mgr := session.NewManager(storage.NewFileStorage(defaultSaveDir()))
+			if err := mgr.SaveAs(fields[1], g); err != nil {
+				fmt.Printf("❌ 保存失败: %v\n", err)
+			} else {
+				fmt.Printf("💾 已保存到存档%q\n", fields[1])
+			}
+		case "读取":
+			if len(fields) != 2 {
+				fmt.Println("用法: 读取 <存档名>")
+				continue
+			}
+			mgr := session.NewManager(storage.NewFileStorage(defaultSaveDir()))
+			ng, err := mgr.Load(fields[1])
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			ng.SetDebug(*flagDebug)
+			ng.SetDisplayStyle(displayStyle)
+			ng.SetLogger(game.StdoutLogger)
+			if g.EconomyMode() {
+				ng.SetEconomyMode(true)
+				ng.AddCoins(g.Coins())
+			}
+			g = ng
+			fmt.Printf("📂 已读取存档%q\n", fields[1])
+		case "存档列表":
+			mgr := session.NewManager(storage.NewFileStorage(defaultSaveDir()))
+			names, err := mgr.ListSaves()
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			if len(names) == 0 {
+				fmt.Println("还没有任何存档 (用\"保存 <名字>\"创建)")
+				continue
+			}
+			for _, name := range names {
+				fmt.Println("  💾 " + name)
+			}
+		case "打包":
+			exportReplayLevel(g)
+		case "导出":
+			name := "board.svg"
+			if len(fields) == 2 {
+				name = fields[1]
+			}
+			f, err := os.Create(name)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			if err := g.ExportSVG(f); err != nil {
+				fmt.Printf("❌ 导出失败: %v\n", err)
+			} else {
+				fmt.Printf("🖼 局面已导出到 %s\n", name)
+			}
+			f.Close()
+		case "再来一局":
+			// 同参数重新开一局: 不带参数用新种子, 带参数用指定种子
+			seed := time.Now().UnixNano()
+			if len(fields) == 2 {
+				n, err := strconv.ParseInt(fields[1], 10, 64)
+				if err != nil {
+					fmt.Println("种子必须是数字")
+					continue
+				}
+				seed = n
+			}
+			ng := game.NewWaterBottleGameWithSeed(g.BottleCount, g.Capacity, g.ColorCount,
+				g.EmptyBottles, g.JarCount, g.JarCapacity, g.UseBags, seed)
+			ng.SetRuleSet(g.Rules)
+			ng.SetStrictMode(g.StrictMode)
+			ng.SetDebug(*flagDebug)
+			ng.SetDisplayStyle(displayStyle)
+			ng.SetLogger(game.StdoutLogger)
+			if g.EconomyMode() {
+				ng.SetEconomyMode(true)
+				ng.AddCoins(g.Coins())
+			}
+			g = ng
+			fmt.Printf("🔄 新的一局 (种子%d), 难度: %s\n", g.Seed(), g.Difficulty())
+		case "分享":
+			if code, err := g.ShareCode(); err != nil {
+				fmt.Printf("❌ 生成分享码失败: %v\n", err)
+			} else {
+				fmt.Println("📋 分享码 (整行复制给朋友, 用\"粘贴导入\"开局):")
+				fmt.Println(code)
+			}
+		case "二维码":
+			if len(fields) == 2 {
+				f, err := os.Create(fields[1])
+				if err != nil {
+					fmt.Printf("❌ 创建文件失败: %v\n", err)
+					continue
+				}
+				err = g.ExportQR(f)
+				f.Close()
+				if err != nil {
+					fmt.Printf("❌ 导出QR码失败: %v\n", err)
+					continue
+				}
+				fmt.Printf("📷 QR码已写入 %s (扫出分享码后用\"粘贴导入\"开局)\n", fields[1])
+				continue
+			}
+			if text, err := g.ExportQRText(); err != nil {
+				fmt.Printf("❌ 生成QR码失败: %v\n", err)
+			} else {
+				fmt.Print(text)
+			}
+		case "粘贴导入":
+			if len(fields) < 2 {
+				fmt.Println("用法: 粘贴导入 <分享码或存档JSON>")
+				continue
+			}
+			ng, err := game.ImportShared(strings.Join(fields[1:], " "))
+			if err != nil {
+				fmt.Printf("❌ 导入失败: %v\n", err)
+				continue
+			}
+			ng.SetStrictMode(g.StrictMode)
+			ng.SetDebug(*flagDebug)
+			ng.SetDisplayStyle(displayStyle)
+			ng.SetLogger(game.StdoutLogger)
+			if g.EconomyMode() {
+				ng.SetEconomyMode(true)
+				ng.AddCoins(g.Coins())
+			}
+			g = ng
+			fmt.Println("📥 已导入对局, 开始游戏")
+		case "外部导入":
+			if len(fields) < 2 {
+				fmt.Println("用法: 外部导入 <字母式编码 / tubes JSON / 数字矩阵>")
+				continue
+			}
+			ng, err := game.ImportPuzzle(strings.Join(fields[1:], " "))
+			if err != nil {
+				fmt.Printf("❌ 导入失败: %v\n", err)
+				continue
+			}
+			ng.SetStrictMode(g.StrictMode)
+			ng.SetDebug(*flagDebug)
+			ng.SetDisplayStyle(displayStyle)
+			ng.SetLogger(game.StdoutLogger)
+			if g.EconomyMode() {
+				ng.SetEconomyMode(true)
+				ng.AddCoins(g.Coins())
+			}
+			g = ng
+			fmt.Printf("📥 已导入外部谜题 (%d瓶%d色), 开始游戏\n", len(g.Bottles), g.ColorCount)
+		case "商店":
+			fmt.Printf("💰 余额%d金币. 罐子价格: 容量2=%d ... 容量8=%d (每格容量加价)\n",
+				g.Coins(), game.JarCost(2), game.JarCost(8))
+			fmt.Println("用法: 买罐 <容量>")
+		case "买罐":
+			if len(fields) != 2 {
+				fmt.Println("用法: 买罐 <容量>")
+				continue
+			}
+			capacity, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("容量必须是数字")
+				continue
+			}
+			if err := g.PurchaseJar(capacity); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		case "作弊解法":
+			if moves, err := g.CheatRevealSolution(); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			} else {
+				for i, m := range moves {
+					fmt.Printf("  第%d步: %s -> %s\n", i+1, g.ContainerName(m.From), g.ContainerName(m.To))
+				}
+			}
+		case "作弊搬运":
+			if len(fields) != 3 {
+				fmt.Println("用法: 作弊搬运 <源瓶> <目标瓶>")
+				continue
+			}
+			from, err1 := strconv.Atoi(fields[1])
+			to, err2 := strconv.Atoi(fields[2])
+			if err1 != nil || err2 != nil {
+				fmt.Println("瓶子编号必须是数字")
+				continue
+			}
+			if err := g.CheatTeleportUnit(from, to); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		case "作弊完成":
+			if len(fields) != 2 {
+				fmt.Println("用法: 作弊完成 <瓶子>")
+				continue
+			}
+			idx, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("瓶子编号必须是数字")
+				continue
+			}
+			if err := g.CheatCompleteBottle(idx); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		case "双手":
+			if len(fields) != 5 {
+				fmt.Println("用法: 双手 <源1> <目标1> <源2> <目标2>")
+				continue
+			}
+			var idx [4]int
+			bad := false
+			for i := 0; i < 4; i++ {
+				n, err := strconv.Atoi(fields[i+1])
+				if err != nil {
+					bad = true
+					break
+				}
+				idx[i] = n
+			}
+			if bad {
+				fmt.Println("瓶子编号必须是数字")
+				continue
+			}
+			if _, _, err := g.PourBoth(idx[0], idx[1], idx[2], idx[3]); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+		case "倒水":
+			if len(fields) != 3 && len(fields) != 4 {
+				fmt.Println("用法: 倒水 <源瓶> <目标瓶> [层数]")
+				continue
+			}
+			if len(fields) == 4 {
+				doPourAmount(g, fields[1], fields[2], fields[3])
+			} else {
+				doPour(g, fields[1], fields[2])
+			}
+		default:
+			if len(fields) == 2 {
+				doPour(g, fields[0], fields[1])
+			} else if from, err := strconv.Atoi(fields[0]); len(fields) == 1 && err == nil {
+				// 只输入一个瓶子编号 = 一键倒水
+				if m, err := g.AutoPour(from); err != nil {
+					fmt.Printf("❌ %v\n", err)
+				} else {
+					fmt.Printf("👆 %s -> %s\n", g.ContainerName(m.From), g.ContainerName(m.To))
+				}
+			} else {
+				fmt.Println("无法识别的命令")
+			}
+		}
+	}
+}
+
+// exportReplayLevel 把当前对局打包成独立关卡文件: 初始局面加上到目前
+// 为止的走子作为作者解法, 方便把偶然玩出来的好局面分享给别人.
+func exportReplayLevel(g *game.WaterBottleGame) {
+	lv, err := pack.FromReplay(fmt.Sprintf("种子%d", g.Seed()), g, g.MoveLog())
+	if err != nil {
+		fmt.Printf("❌ 打包失败: %v\n", err)
+		return
+	}
+	data, err := json.MarshalIndent(lv, "", "  ")
+	if err != nil {
+		fmt.Printf("❌ 打包失败: %v\n", err)
+		return
+	}
+	name := fmt.Sprintf("level_%d.json", g.Seed())
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		fmt.Printf("❌ 写入%s失败: %v\n", name, err)
+		return
+	}
+	fmt.Printf("📦 已导出关卡 %s (含%d步作者解法)\n", name, len(lv.AuthorSolution))
+}
+
+// defaultSaveDir 玩家档案目录, 取不到用户目录时退回当前目录下的隐藏目录
+func defaultSaveDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".hello-word"
+	}
+	return filepath.Join(home, ".hello-word")
+}
+
+// runCampaign 主线战役模式: 按内置关卡顺序逐关推进,
+// 每通一关就保存进度, 中途退出下次从断点继续.
+func runCampaign() error {
+	scanner := bufio.NewScanner(os.Stdin)
+	store := storage.NewFileStorage(defaultSaveDir())
+	c, err := campaign.Load(store)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("=== 主线战役 === (共%d关, 已通%d关)\n", c.LevelCount(), c.CompletedCount())
+	for {
+		lv, idx, ok := c.NextLevel()
+		if !ok {
+			fmt.Println("🏆 战役已全部通关!")
+			break
+		}
+		fmt.Printf("\n--- 第%d/%d关: %s ---\n", idx+1, c.LevelCount(), lv.Name)
+		g, err := lv.NewGame()
+		if err != nil {
+			return err
+		}
+		g.SetStrictMode(*flagStrict)
+		g.SetDisplayStyle(displayStyle)
+		g.SetDebug(*flagDebug)
+		g.SetLogger(game.StdoutLogger)
+		par := 0
+		if r := g.EstimateDifficulty(); r.Solvable {
+			par = r.OptimalMoves
+		}
+		start := time.Now()
+		code := playGame(g, scanner)
+		recordProfile(g, code.IsWin())
+		if !code.IsWin() {
+			fmt.Println("战役暂停, 进度已保存")
+			break
+		}
+		result := pack.LevelResult{Name: lv.Name, Won: true, Moves: g.MoveCount, Par: par, Elapsed: time.Since(start)}
+		if err := c.MarkCompleted(result); err != nil {
+			return err
+		}
+	}
+	fmt.Print(c.Report().Render())
+	return nil
+}
+
+// doPour 解析并执行一次倒水命令
+func doPour(g *game.WaterBottleGame, fromStr, toStr string) {
+	from, err1 := strconv.Atoi(fromStr)
+	to, err2 := strconv.Atoi(toStr)
+	if err1 != nil || err2 != nil {
+		fmt.Println("瓶子编号必须是数字")
+		return
+	}
+	if g.WouldDeadlock(from, to) {
+		fmt.Println("⚠️ 注意: 这步倒完就没有任何可行操作了")
+	}
+	if _, err := g.Pour(from, to); err != nil {
+		fmt.Printf("❌ 倒水失败: %v\n", err)
+	}
+}
+
+// doPourAmount 解析并执行一次指定层数的部分倒水
+func doPourAmount(g *game.WaterBottleGame, fromStr, toStr, amountStr string) {
+	from, err1 := strconv.Atoi(fromStr)
+	to, err2 := strconv.Atoi(toStr)
+	amount, err3 := strconv.Atoi(amountStr)
+	if err1 != nil || err2 != nil || err3 != nil {
+		fmt.Println("瓶子编号和层数必须是数字")
+		return
+	}
+	if _, err := g.PourAmount(from, to, amount); err != nil {
+		fmt.Printf("❌ 倒水失败: %v\n", err)
+	}
+}
+
+// autoSolvePlayback 让电脑接管: 先求解, 再把解法一步一步演出来
+func autoSolvePlayback(g *game.WaterBottleGame) {
+	moves, ok := g.Solve()
+	if !ok {
+		fmt.Println("🤖 求解器没有找到解法")
+		return
+	}
+	fmt.Printf("🤖 找到%d步解法, 开始回放...\n", len(moves))
+	err := g.PlaySolution(moves, 500*time.Millisecond, game.RendererFunc(
+		func(g *game.WaterBottleGame, step int, m game.Move) {
+			fmt.Printf("  第%d/%d步: %s -> %s\n", step, len(moves), g.ContainerName(m.From), g.ContainerName(m.To))
+			g.PrintState()
+		}))
+	if err != nil {
+		fmt.Printf("❌ 回放中断: %v\n", err)
+	}
+}
+
+// demonstrateBasicSolver 用机器人策略演示自动求解. 以前这里手写了
+// 一个朴素循环, 现在换成引擎的Player策略+RunBot框架, 策略可选.
+func demonstrateBasicSolver(g *game.WaterBottleGame, strategy string) {
+	if strategy == "" {
+		strategy = "贪心"
+	}
+	bot := game.BotByName(strategy, *flagSeed)
+	if bot == nil {
+		fmt.Printf("未知的策略: %s (可选 贪心/随机/前瞻)\n", strategy)
+		return
+	}
+	fmt.Printf("=== %s机器人演示 ===\n", bot.Name())
+	res := game.RunBot(g, bot, 50)
+	for i, m := range res.Moves {
+		fmt.Printf("  第%d步: %s -> %s\n", i+1, g.ContainerName(m.From), g.ContainerName(m.To))
+	}
+	switch {
+	case res.Won:
+		fmt.Println("🎉 求解成功!")
+	case res.Stuck:
+		fmt.Println("  没有可行的操作, 演示结束")
+	default:
+		fmt.Println("  步数用尽, 演示结束")
+	}
+}